@@ -13,8 +13,13 @@ import (
 	httpAdapter "chess-backend/internal/adapters/http"
 	"chess-backend/internal/adapters/mongodb"
 	"chess-backend/internal/adapters/redis"
+	"chess-backend/internal/adapters/websocket"
 	"chess-backend/internal/application/auth"
+	"chess-backend/internal/application/challenge"
 	"chess-backend/internal/application/game"
+	"chess-backend/internal/application/matchmaking"
+	gameDomain "chess-backend/internal/domain/game"
+	"chess-backend/internal/ports/events"
 
 	"github.com/joho/godotenv"
 )
@@ -80,13 +85,49 @@ func main() {
 	userRepo := mongodb.NewUserRepository(mongoClient, mongoConfig.Database)
 	sessionRepo := redis.NewSessionRepository(redisClient)
 	gameRepo := mongodb.NewGameRepository(mongoClient.Database(mongoConfig.Database).Collection("games"))
+	ratingRepo := mongodb.NewRatingHistoryRepository(mongoClient.Database(mongoConfig.Database).Collection("rating_history"))
+	challengeRepo := mongodb.NewChallengeRepository(mongoClient.Database(mongoConfig.Database).Collection("challenges"))
+	spectatorRepo := redis.NewSpectatorRepository(redisClient)
+	presenceRepo := redis.NewPresenceRepository(redisClient)
+	seekRepo := redis.NewSeekRepository(redisClient)
+	gameEvents := redis.NewGameEventPublisher(redisClient)
 
 	// Initialize application services
 	authService := auth.NewAuthService(userRepo, sessionRepo)
-	gameService := game.NewGameService(gameRepo)
+	gameService := game.NewGameService(gameRepo, userRepo, ratingRepo, spectatorRepo, presenceRepo, gameEvents)
+	matchmakingService := matchmaking.NewMatchmakingService(seekRepo, userRepo, gameService, gameEvents)
+	challengeService := challenge.NewChallengeService(challengeRepo, userRepo, gameService, gameEvents, getEnvDuration("CHALLENGE_TTL", 24*time.Hour))
+
+	// Start background workers
+	flagSweeper := game.NewWorker("flag sweeper", getEnvDuration("FLAG_SWEEP_INTERVAL", time.Second), gameService.ExpireTimedOutGames)
+	flagSweeper.Start()
+	correspondenceSweeper := game.NewWorker("correspondence sweeper", getEnvDuration("CORRESPONDENCE_SWEEP_INTERVAL", time.Minute), gameService.ExpireCorrespondenceGames)
+	correspondenceSweeper.Start()
+	abortWindow := getEnvDuration("ABORT_WINDOW", 30*time.Second)
+	abortSweeper := game.NewWorker("abort sweeper", getEnvDuration("ABORT_SWEEP_INTERVAL", 5*time.Second), func(ctx context.Context) (int, error) {
+		return gameService.AbortUnplayedGames(ctx, abortWindow)
+	})
+	abortSweeper.Start()
+	waitingGameTTL := getEnvDuration("WAITING_GAME_TTL", time.Hour)
+	waitingSweeper := game.NewWorker("waiting game sweeper", getEnvDuration("WAITING_SWEEP_INTERVAL", time.Minute), func(ctx context.Context) (int, error) {
+		return gameService.ExpireWaitingGames(ctx, waitingGameTTL)
+	})
+	waitingSweeper.Start()
+	matchmaker := game.NewWorker("matchmaker", getEnvDuration("MATCHMAKING_INTERVAL", 2*time.Second), matchmakingService.MatchSeeks)
+	matchmaker.Start()
+	challengeExpirer := game.NewWorker("challenge expirer", getEnvDuration("CHALLENGE_SWEEP_INTERVAL", time.Minute), challengeService.ExpireChallenges)
+	challengeExpirer.Start()
+	ratingRetrier := game.NewWorker("rating retrier", getEnvDuration("RATING_RETRY_INTERVAL", time.Minute), gameService.RateGames)
+	ratingRetrier.Start()
+
+	// Deliver game events from every instance to the sockets held by this one
+	hub := websocket.NewHub()
+	eventsCtx, stopEvents := context.WithCancel(context.Background())
+	defer stopEvents()
+	go subscribeGameEvents(eventsCtx, gameEvents, hub.Broadcast)
 
 	// Initialize HTTP server with dependency injection
-	server := httpAdapter.NewServer(authService, gameService)
+	server := httpAdapter.NewServer(authService, gameService, matchmakingService, challengeService, hub)
 	router := server.GetRouter()
 
 	// Get port from environment
@@ -127,6 +168,28 @@ func main() {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	if err := flagSweeper.Stop(ctx); err != nil {
+		log.Printf("Flag sweeper did not stop cleanly: %v", err)
+	}
+	if err := correspondenceSweeper.Stop(ctx); err != nil {
+		log.Printf("Correspondence sweeper did not stop cleanly: %v", err)
+	}
+	if err := abortSweeper.Stop(ctx); err != nil {
+		log.Printf("Abort sweeper did not stop cleanly: %v", err)
+	}
+	if err := waitingSweeper.Stop(ctx); err != nil {
+		log.Printf("Waiting game sweeper did not stop cleanly: %v", err)
+	}
+	if err := matchmaker.Stop(ctx); err != nil {
+		log.Printf("Matchmaker did not stop cleanly: %v", err)
+	}
+	if err := challengeExpirer.Stop(ctx); err != nil {
+		log.Printf("Challenge expirer did not stop cleanly: %v", err)
+	}
+	if err := ratingRetrier.Stop(ctx); err != nil {
+		log.Printf("Rating retrier did not stop cleanly: %v", err)
+	}
+
 	log.Println("Server exited")
 }
 
@@ -137,3 +200,49 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+// subscribeGameEvents delivers the events published by every instance to handler until
+// ctx is cancelled. Whenever the subscription stops, it is set up again after a delay
+// that doubles on every failure in a row.
+func subscribeGameEvents(ctx context.Context, publisher events.GameEventPublisher, handler func(gameDomain.Event)) {
+	const (
+		minBackoff = time.Second
+		maxBackoff = 30 * time.Second
+	)
+
+	backoff := minBackoff
+	for {
+		started := time.Now()
+		err := publisher.Subscribe(ctx, handler)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// A subscription that worked for a while is retried quickly
+		if time.Since(started) > maxBackoff {
+			backoff = minBackoff
+		}
+		log.Printf("Game event subscription stopped, retrying in %s: %v", backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, maxBackoff)
+	}
+}
+
+// getEnvDuration gets a duration environment variable (e.g. "30s") with fallback
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("Warning: invalid %s %q, using %s", key, value, fallback)
+		return fallback
+	}
+	return d
+}
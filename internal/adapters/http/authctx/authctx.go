@@ -0,0 +1,27 @@
+// Package authctx carries the authenticated user through request contexts.
+// It is shared by the HTTP, WebSocket and SSE adapters, which cannot import the
+// http package that sets it.
+package authctx
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// contextKey is used for context keys to avoid collisions
+type contextKey string
+
+// UserIDKey is the context key for the authenticated user's ID
+const UserIDKey contextKey = "user_id"
+
+// WithUserID returns a copy of the context carrying the authenticated user's ID
+func WithUserID(ctx context.Context, userID primitive.ObjectID) context.Context {
+	return context.WithValue(ctx, UserIDKey, userID)
+}
+
+// UserIDFromContext extracts the authenticated user's ID from the context
+func UserIDFromContext(ctx context.Context) (primitive.ObjectID, bool) {
+	userID, ok := ctx.Value(UserIDKey).(primitive.ObjectID)
+	return userID, ok
+}
@@ -0,0 +1,128 @@
+// Package challenge contains HTTP handlers for direct challenges between players.
+// This is part of the Adapters layer in Hexagonal Architecture.
+package challenge
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"chess-backend/internal/adapters/http/authctx"
+	"chess-backend/internal/ports/services"
+	"chess-backend/internal/utils"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ChallengeHandlers contains all HTTP handlers for challenge operations
+type ChallengeHandlers struct {
+	challengeService services.ChallengeService
+}
+
+// NewChallengeHandlers creates a new instance of ChallengeHandlers
+func NewChallengeHandlers(challengeService services.ChallengeService) *ChallengeHandlers {
+	return &ChallengeHandlers{
+		challengeService: challengeService,
+	}
+}
+
+// CreateChallengeHandler handles POST /api/challenges
+func (h *ChallengeHandlers) CreateChallengeHandler(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context (set by auth middleware)
+	userID, ok := authctx.UserIDFromContext(r.Context())
+	if !ok {
+		utils.Response.WriteUnauthorized(w, "User not authenticated")
+		return
+	}
+
+	// Parse request body
+	var req services.CreateChallengeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.Response.WriteBadRequest(w, "Invalid request body")
+		return
+	}
+	req.ChallengerID = userID
+
+	// Call service
+	challengeResponse, err := h.challengeService.CreateChallenge(r.Context(), req)
+	if err != nil {
+		utils.Response.WriteBadRequest(w, err.Error())
+		return
+	}
+
+	utils.Response.WriteCreated(w, challengeResponse.Message, challengeResponse)
+}
+
+// ListChallengesHandler handles GET /api/challenges
+func (h *ChallengeHandlers) ListChallengesHandler(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context
+	userID, ok := authctx.UserIDFromContext(r.Context())
+	if !ok {
+		utils.Response.WriteUnauthorized(w, "User not authenticated")
+		return
+	}
+
+	// Call service
+	challenges, err := h.challengeService.ListChallenges(r.Context(), userID)
+	if err != nil {
+		utils.Response.WriteBadRequest(w, err.Error())
+		return
+	}
+
+	utils.Response.WriteSuccess(w, "Challenges retrieved successfully", challenges)
+}
+
+// GetChallengeHandler handles GET /api/challenges/{challengeId}
+func (h *ChallengeHandlers) GetChallengeHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleChallenge(w, r, h.challengeService.GetChallenge)
+}
+
+// AcceptChallengeHandler handles POST /api/challenges/{challengeId}/accept
+func (h *ChallengeHandlers) AcceptChallengeHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleChallenge(w, r, h.challengeService.AcceptChallenge)
+}
+
+// DeclineChallengeHandler handles POST /api/challenges/{challengeId}/decline
+func (h *ChallengeHandlers) DeclineChallengeHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleChallenge(w, r, h.challengeService.DeclineChallenge)
+}
+
+// CancelChallengeHandler handles POST /api/challenges/{challengeId}/cancel
+func (h *ChallengeHandlers) CancelChallengeHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleChallenge(w, r, h.challengeService.CancelChallenge)
+}
+
+// handleChallenge runs a service operation on the challenge named in the URL on
+// behalf of the authenticated user
+func (h *ChallengeHandlers) handleChallenge(w http.ResponseWriter, r *http.Request, operation func(ctx context.Context, challengeID, userID primitive.ObjectID) (*services.ChallengeResponse, error)) {
+	// Get user ID from context
+	userID, ok := authctx.UserIDFromContext(r.Context())
+	if !ok {
+		utils.Response.WriteUnauthorized(w, "User not authenticated")
+		return
+	}
+
+	// Get challenge ID from URL
+	vars := mux.Vars(r)
+	challengeIDStr, exists := vars["challengeId"]
+	if !exists {
+		utils.Response.WriteBadRequest(w, "Challenge ID is required")
+		return
+	}
+
+	challengeID, err := primitive.ObjectIDFromHex(challengeIDStr)
+	if err != nil {
+		utils.Response.WriteBadRequest(w, "Invalid challenge ID format")
+		return
+	}
+
+	// Call service
+	challengeResponse, err := operation(r.Context(), challengeID, userID)
+	if err != nil {
+		utils.Response.WriteBadRequest(w, err.Error())
+		return
+	}
+
+	utils.Response.WriteSuccess(w, challengeResponse.Message, challengeResponse)
+}
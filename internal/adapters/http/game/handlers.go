@@ -3,10 +3,16 @@
 package game
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 
+	gameDomain "chess-backend/internal/domain/game"
+	"chess-backend/internal/adapters/http/authctx"
+	"chess-backend/internal/domain/rating"
 	"chess-backend/internal/ports/services"
 	"chess-backend/internal/utils"
 
@@ -29,15 +35,35 @@ func NewGameHandlers(gameService services.GameService) *GameHandlers {
 // CreateGameHandler handles POST /api/game/create
 func (h *GameHandlers) CreateGameHandler(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context (set by auth middleware)
-	userID, ok := r.Context().Value("user_id").(primitive.ObjectID)
+	userID, ok := authctx.UserIDFromContext(r.Context())
 	if !ok {
 		utils.Response.WriteUnauthorized(w, "User not authenticated")
 		return
 	}
 
+	// Parse optional game settings
+	var settings struct {
+		TimeControl     *gameDomain.TimeControl    `json:"time_control"`
+		AllowSpectators *bool                      `json:"allow_spectators"`
+		Rated           bool                       `json:"rated"`
+		Color           gameDomain.ColorPreference `json:"color"`
+		Private         bool                       `json:"private"`
+		AllowTakebacks  *bool                      `json:"allow_takebacks"`
+	}
+	if decodeErr := json.NewDecoder(r.Body).Decode(&settings); decodeErr != nil && decodeErr != io.EOF {
+		utils.Response.WriteBadRequest(w, "Invalid request body")
+		return
+	}
+
 	// Create game request
 	req := services.CreateGameRequest{
-		PlayerID: userID,
+		PlayerID:        userID,
+		TimeControl:     settings.TimeControl,
+		AllowSpectators: settings.AllowSpectators,
+		Rated:           settings.Rated,
+		Color:           settings.Color,
+		Private:         settings.Private,
+		AllowTakebacks:  settings.AllowTakebacks,
 	}
 
 	// Call service
@@ -53,7 +79,7 @@ func (h *GameHandlers) CreateGameHandler(w http.ResponseWriter, r *http.Request)
 // JoinGameHandler handles POST /api/game/join/{gameId}
 func (h *GameHandlers) JoinGameHandler(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context
-	userID, ok := r.Context().Value("user_id").(primitive.ObjectID)
+	userID, ok := authctx.UserIDFromContext(r.Context())
 	if !ok {
 		utils.Response.WriteUnauthorized(w, "User not authenticated")
 		return
@@ -73,10 +99,20 @@ func (h *GameHandlers) JoinGameHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Parse the invite code, only needed for private games
+	var body struct {
+		InviteCode string `json:"invite_code"`
+	}
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil && decodeErr != io.EOF {
+		utils.Response.WriteBadRequest(w, "Invalid request body")
+		return
+	}
+
 	// Create join request
 	req := services.JoinGameRequest{
-		GameID:   gameID,
-		PlayerID: userID,
+		GameID:     gameID,
+		PlayerID:   userID,
+		InviteCode: body.InviteCode,
 	}
 
 	// Call service
@@ -92,7 +128,7 @@ func (h *GameHandlers) JoinGameHandler(w http.ResponseWriter, r *http.Request) {
 // GetGameHandler handles GET /api/game/{gameId}
 func (h *GameHandlers) GetGameHandler(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context
-	userID, ok := r.Context().Value("user_id").(primitive.ObjectID)
+	userID, ok := authctx.UserIDFromContext(r.Context())
 	if !ok {
 		utils.Response.WriteUnauthorized(w, "User not authenticated")
 		return
@@ -125,7 +161,7 @@ func (h *GameHandlers) GetGameHandler(w http.ResponseWriter, r *http.Request) {
 // MoveHandler handles POST /api/game/{gameId}/move
 func (h *GameHandlers) MoveHandler(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context
-	userID, ok := r.Context().Value("user_id").(primitive.ObjectID)
+	userID, ok := authctx.UserIDFromContext(r.Context())
 	if !ok {
 		utils.Response.WriteUnauthorized(w, "User not authenticated")
 		return
@@ -147,10 +183,11 @@ func (h *GameHandlers) MoveHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Parse request body
 	var moveData struct {
-		From     string `json:"from"`
-		To       string `json:"to"`
-		Piece    string `json:"piece"`
-		Notation string `json:"notation"`
+		Move      string `json:"move"`
+		From      string `json:"from"`
+		To        string `json:"to"`
+		Piece     string `json:"piece"`
+		Promotion string `json:"promotion"`
 	}
 
 	if decodeErr := json.NewDecoder(r.Body).Decode(&moveData); decodeErr != nil {
@@ -160,17 +197,22 @@ func (h *GameHandlers) MoveHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Create move request
 	req := services.MakeMoveRequest{
-		GameID:   gameID,
-		PlayerID: userID,
-		From:     moveData.From,
-		To:       moveData.To,
-		Piece:    moveData.Piece,
-		Notation: moveData.Notation,
+		GameID:    gameID,
+		PlayerID:  userID,
+		Move:      moveData.Move,
+		From:      moveData.From,
+		To:        moveData.To,
+		Piece:     moveData.Piece,
+		Promotion: moveData.Promotion,
 	}
 
 	// Call service
 	moveResponse, err := h.gameService.MakeMove(r.Context(), req)
 	if err != nil {
+		if errors.Is(err, gameDomain.ErrIllegalMove) {
+			utils.Response.WriteUnprocessableEntity(w, err.Error())
+			return
+		}
 		utils.Response.WriteBadRequest(w, err.Error())
 		return
 	}
@@ -181,7 +223,7 @@ func (h *GameHandlers) MoveHandler(w http.ResponseWriter, r *http.Request) {
 // ResignGameHandler handles POST /api/game/{gameId}/resign
 func (h *GameHandlers) ResignGameHandler(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context
-	userID, ok := r.Context().Value("user_id").(primitive.ObjectID)
+	userID, ok := authctx.UserIDFromContext(r.Context())
 	if !ok {
 		utils.Response.WriteUnauthorized(w, "User not authenticated")
 		return
@@ -217,10 +259,123 @@ func (h *GameHandlers) ResignGameHandler(w http.ResponseWriter, r *http.Request)
 	utils.Response.WriteSuccess(w, resignResponse.Message, resignResponse)
 }
 
+// ClaimDrawHandler handles POST /api/game/{gameId}/claim-draw
+func (h *GameHandlers) ClaimDrawHandler(w http.ResponseWriter, r *http.Request) {
+	h.handlePlayerAction(w, r, func(ctx context.Context, gameID, userID primitive.ObjectID) (*services.GameResponse, error) {
+		return h.gameService.ClaimDraw(ctx, services.ClaimDrawRequest{GameID: gameID, PlayerID: userID})
+	})
+}
+
+// AbortGameHandler handles POST /api/game/{gameId}/abort
+func (h *GameHandlers) AbortGameHandler(w http.ResponseWriter, r *http.Request) {
+	h.handlePlayerAction(w, r, func(ctx context.Context, gameID, userID primitive.ObjectID) (*services.GameResponse, error) {
+		return h.gameService.AbortGame(ctx, services.AbortGameRequest{GameID: gameID, PlayerID: userID})
+	})
+}
+
+// CancelGameHandler handles DELETE /api/game/{gameId}
+func (h *GameHandlers) CancelGameHandler(w http.ResponseWriter, r *http.Request) {
+	h.handlePlayerAction(w, r, func(ctx context.Context, gameID, userID primitive.ObjectID) (*services.GameResponse, error) {
+		return h.gameService.CancelGame(ctx, services.CancelGameRequest{GameID: gameID, PlayerID: userID})
+	})
+}
+
+// ClaimAbandonmentHandler handles POST /api/game/{gameId}/claim-abandonment
+func (h *GameHandlers) ClaimAbandonmentHandler(w http.ResponseWriter, r *http.Request) {
+	// Parse the claim, a win or a draw
+	var body struct {
+		Claim gameDomain.AbandonmentClaim `json:"claim"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		utils.Response.WriteBadRequest(w, "Invalid request body")
+		return
+	}
+
+	h.handlePlayerAction(w, r, func(ctx context.Context, gameID, userID primitive.ObjectID) (*services.GameResponse, error) {
+		return h.gameService.ClaimAbandonment(ctx, services.ClaimAbandonmentRequest{GameID: gameID, PlayerID: userID, Claim: body.Claim})
+	})
+}
+
+// OfferDrawHandler handles POST /api/game/{gameId}/draw
+func (h *GameHandlers) OfferDrawHandler(w http.ResponseWriter, r *http.Request) {
+	h.handlePlayerAction(w, r, func(ctx context.Context, gameID, userID primitive.ObjectID) (*services.GameResponse, error) {
+		return h.gameService.OfferDraw(ctx, services.DrawOfferRequest{GameID: gameID, PlayerID: userID})
+	})
+}
+
+// AcceptDrawHandler handles POST /api/game/{gameId}/draw/accept
+func (h *GameHandlers) AcceptDrawHandler(w http.ResponseWriter, r *http.Request) {
+	h.handlePlayerAction(w, r, func(ctx context.Context, gameID, userID primitive.ObjectID) (*services.GameResponse, error) {
+		return h.gameService.AcceptDraw(ctx, services.DrawOfferRequest{GameID: gameID, PlayerID: userID})
+	})
+}
+
+// DeclineDrawHandler handles POST /api/game/{gameId}/draw/decline
+func (h *GameHandlers) DeclineDrawHandler(w http.ResponseWriter, r *http.Request) {
+	h.handlePlayerAction(w, r, func(ctx context.Context, gameID, userID primitive.ObjectID) (*services.GameResponse, error) {
+		return h.gameService.DeclineDraw(ctx, services.DrawOfferRequest{GameID: gameID, PlayerID: userID})
+	})
+}
+
+// RequestTakebackHandler handles POST /api/game/{gameId}/takeback
+func (h *GameHandlers) RequestTakebackHandler(w http.ResponseWriter, r *http.Request) {
+	h.handlePlayerAction(w, r, func(ctx context.Context, gameID, userID primitive.ObjectID) (*services.GameResponse, error) {
+		return h.gameService.RequestTakeback(ctx, services.TakebackRequest{GameID: gameID, PlayerID: userID})
+	})
+}
+
+// AcceptTakebackHandler handles POST /api/game/{gameId}/takeback/accept
+func (h *GameHandlers) AcceptTakebackHandler(w http.ResponseWriter, r *http.Request) {
+	h.handlePlayerAction(w, r, func(ctx context.Context, gameID, userID primitive.ObjectID) (*services.GameResponse, error) {
+		return h.gameService.AcceptTakeback(ctx, services.TakebackRequest{GameID: gameID, PlayerID: userID})
+	})
+}
+
+// DeclineTakebackHandler handles POST /api/game/{gameId}/takeback/decline
+func (h *GameHandlers) DeclineTakebackHandler(w http.ResponseWriter, r *http.Request) {
+	h.handlePlayerAction(w, r, func(ctx context.Context, gameID, userID primitive.ObjectID) (*services.GameResponse, error) {
+		return h.gameService.DeclineTakeback(ctx, services.TakebackRequest{GameID: gameID, PlayerID: userID})
+	})
+}
+
+// handlePlayerAction runs a service operation on the game named in the URL on
+// behalf of the authenticated player
+func (h *GameHandlers) handlePlayerAction(w http.ResponseWriter, r *http.Request, operation func(ctx context.Context, gameID, userID primitive.ObjectID) (*services.GameResponse, error)) {
+	// Get user ID from context
+	userID, ok := authctx.UserIDFromContext(r.Context())
+	if !ok {
+		utils.Response.WriteUnauthorized(w, "User not authenticated")
+		return
+	}
+
+	// Get game ID from URL
+	vars := mux.Vars(r)
+	gameIDStr, exists := vars["gameId"]
+	if !exists {
+		utils.Response.WriteBadRequest(w, "Game ID is required")
+		return
+	}
+
+	gameID, err := primitive.ObjectIDFromHex(gameIDStr)
+	if err != nil {
+		utils.Response.WriteBadRequest(w, "Invalid game ID format")
+		return
+	}
+
+	// Call service
+	actionResponse, err := operation(r.Context(), gameID, userID)
+	if err != nil {
+		utils.Response.WriteBadRequest(w, err.Error())
+		return
+	}
+
+	utils.Response.WriteSuccess(w, actionResponse.Message, actionResponse)
+}
+
 // ListPlayerGamesHandler handles GET /api/game/my-games
 func (h *GameHandlers) ListPlayerGamesHandler(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context
-	userID, ok := r.Context().Value("user_id").(primitive.ObjectID)
+	userID, ok := authctx.UserIDFromContext(r.Context())
 	if !ok {
 		utils.Response.WriteUnauthorized(w, "User not authenticated")
 		return
@@ -311,7 +466,7 @@ func (h *GameHandlers) ListActiveGamesHandler(w http.ResponseWriter, r *http.Req
 // GetGameHistoryHandler handles GET /api/game/{gameId}/history
 func (h *GameHandlers) GetGameHistoryHandler(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context
-	userID, ok := r.Context().Value("user_id").(primitive.ObjectID)
+	userID, ok := authctx.UserIDFromContext(r.Context())
 	if !ok {
 		utils.Response.WriteUnauthorized(w, "User not authenticated")
 		return
@@ -341,10 +496,43 @@ func (h *GameHandlers) GetGameHistoryHandler(w http.ResponseWriter, r *http.Requ
 	utils.Response.WriteSuccess(w, "Game history retrieved successfully", moves)
 }
 
+// GetLegalMovesHandler handles GET /api/game/{gameId}/legal-moves?from=e2
+func (h *GameHandlers) GetLegalMovesHandler(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context
+	userID, ok := authctx.UserIDFromContext(r.Context())
+	if !ok {
+		utils.Response.WriteUnauthorized(w, "User not authenticated")
+		return
+	}
+
+	// Get game ID from URL
+	vars := mux.Vars(r)
+	gameIDStr, exists := vars["gameId"]
+	if !exists {
+		utils.Response.WriteBadRequest(w, "Game ID is required")
+		return
+	}
+
+	gameID, err := primitive.ObjectIDFromHex(gameIDStr)
+	if err != nil {
+		utils.Response.WriteBadRequest(w, "Invalid game ID format")
+		return
+	}
+
+	// Call service
+	moves, err := h.gameService.GetLegalMoves(r.Context(), gameID, userID, r.URL.Query().Get("from"))
+	if err != nil {
+		utils.Response.WriteBadRequest(w, err.Error())
+		return
+	}
+
+	utils.Response.WriteSuccess(w, "Legal moves retrieved successfully", moves)
+}
+
 // GetPlayerStatsHandler handles GET /api/game/stats
 func (h *GameHandlers) GetPlayerStatsHandler(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context
-	userID, ok := r.Context().Value("user_id").(primitive.ObjectID)
+	userID, ok := authctx.UserIDFromContext(r.Context())
 	if !ok {
 		utils.Response.WriteUnauthorized(w, "User not authenticated")
 		return
@@ -358,4 +546,36 @@ func (h *GameHandlers) GetPlayerStatsHandler(w http.ResponseWriter, r *http.Requ
 	}
 
 	utils.Response.WriteSuccess(w, "Player stats retrieved successfully", stats)
+}
+
+// GetRatingHistoryHandler handles GET /api/game/rating-history?category=blitz
+func (h *GameHandlers) GetRatingHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context
+	userID, ok := authctx.UserIDFromContext(r.Context())
+	if !ok {
+		utils.Response.WriteUnauthorized(w, "User not authenticated")
+		return
+	}
+
+	category := rating.Category(r.URL.Query().Get("category"))
+	if category == "" {
+		utils.Response.WriteBadRequest(w, "Rating category is required")
+		return
+	}
+
+	limit := 20
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	// Call service
+	history, err := h.gameService.GetRatingHistory(r.Context(), userID, category, limit)
+	if err != nil {
+		utils.Response.WriteBadRequest(w, err.Error())
+		return
+	}
+
+	utils.Response.WriteSuccess(w, "Rating history retrieved successfully", history)
 }
\ No newline at end of file
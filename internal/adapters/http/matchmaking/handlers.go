@@ -0,0 +1,86 @@
+// Package matchmaking contains HTTP handlers for the matchmaking queue.
+// This is part of the Adapters layer in Hexagonal Architecture.
+package matchmaking
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"chess-backend/internal/adapters/http/authctx"
+	"chess-backend/internal/ports/services"
+	"chess-backend/internal/utils"
+)
+
+// MatchmakingHandlers contains all HTTP handlers for matchmaking operations
+type MatchmakingHandlers struct {
+	matchmakingService services.MatchmakingService
+}
+
+// NewMatchmakingHandlers creates a new instance of MatchmakingHandlers
+func NewMatchmakingHandlers(matchmakingService services.MatchmakingService) *MatchmakingHandlers {
+	return &MatchmakingHandlers{
+		matchmakingService: matchmakingService,
+	}
+}
+
+// SeekHandler handles POST /api/matchmaking/seek
+func (h *MatchmakingHandlers) SeekHandler(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context (set by auth middleware)
+	userID, ok := authctx.UserIDFromContext(r.Context())
+	if !ok {
+		utils.Response.WriteUnauthorized(w, "User not authenticated")
+		return
+	}
+
+	// Parse the optional time control, omitted for an untimed game, and whether to play rated
+	var req services.SeekRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		utils.Response.WriteBadRequest(w, "Invalid request body")
+		return
+	}
+	req.PlayerID = userID
+
+	seekResponse, err := h.matchmakingService.Seek(r.Context(), req)
+	if err != nil {
+		utils.Response.WriteBadRequest(w, err.Error())
+		return
+	}
+
+	utils.Response.WriteSuccess(w, seekResponse.Message, seekResponse)
+}
+
+// GetSeekHandler handles GET /api/matchmaking/seek
+func (h *MatchmakingHandlers) GetSeekHandler(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context
+	userID, ok := authctx.UserIDFromContext(r.Context())
+	if !ok {
+		utils.Response.WriteUnauthorized(w, "User not authenticated")
+		return
+	}
+
+	seekResponse, err := h.matchmakingService.GetSeek(r.Context(), userID)
+	if err != nil {
+		utils.Response.WriteNotFound(w, err.Error())
+		return
+	}
+
+	utils.Response.WriteSuccess(w, seekResponse.Message, seekResponse)
+}
+
+// CancelSeekHandler handles DELETE /api/matchmaking/seek
+func (h *MatchmakingHandlers) CancelSeekHandler(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context
+	userID, ok := authctx.UserIDFromContext(r.Context())
+	if !ok {
+		utils.Response.WriteUnauthorized(w, "User not authenticated")
+		return
+	}
+
+	if err := h.matchmakingService.CancelSeek(r.Context(), userID); err != nil {
+		utils.Response.WriteBadRequest(w, err.Error())
+		return
+	}
+
+	utils.Response.WriteSuccess(w, "Seek cancelled", nil)
+}
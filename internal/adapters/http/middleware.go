@@ -8,18 +8,14 @@ import (
 	"net/http"
 	"time"
 
+	"chess-backend/internal/adapters/http/authctx"
 	"chess-backend/internal/ports/services"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// contextKey is used for context keys to avoid collisions
-type contextKey string
-
-const (
-	// UserIDKey is the context key for user ID
-	UserIDKey contextKey = "user_id"
-)
+// UserIDKey is the context key for user ID
+const UserIDKey = authctx.UserIDKey
 
 // AuthMiddleware provides authentication middleware
 type AuthMiddleware struct {
@@ -49,7 +45,7 @@ func (m *AuthMiddleware) RequireAuth(next http.Handler) http.Handler {
 		}
 
 		// Add user ID to context
-		ctx := context.WithValue(r.Context(), UserIDKey, userID)
+		ctx := authctx.WithUserID(r.Context(), userID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -72,15 +68,14 @@ func (m *AuthMiddleware) OptionalAuth(next http.Handler) http.Handler {
 		}
 
 		// Add user ID to context
-		ctx := context.WithValue(r.Context(), UserIDKey, userID)
+		ctx := authctx.WithUserID(r.Context(), userID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
 // GetUserIDFromContext extracts user ID from request context
 func GetUserIDFromContext(ctx context.Context) (primitive.ObjectID, bool) {
-	userID, ok := ctx.Value(UserIDKey).(primitive.ObjectID)
-	return userID, ok
+	return authctx.UserIDFromContext(ctx)
 }
 
 // LoggingMiddleware logs HTTP requests
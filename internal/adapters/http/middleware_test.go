@@ -0,0 +1,59 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"chess-backend/internal/adapters/http/matchmaking"
+	"chess-backend/internal/ports/services"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// stubAuthService accepts a single session
+type stubAuthService struct {
+	services.AuthService
+	sessionID string
+	userID    primitive.ObjectID
+}
+
+func (s *stubAuthService) ValidateSession(ctx context.Context, sessionID string) (primitive.ObjectID, error) {
+	if sessionID != s.sessionID {
+		return primitive.NilObjectID, errors.New("invalid session")
+	}
+	return s.userID, nil
+}
+
+// stubMatchmakingService records the player whose seek is requested
+type stubMatchmakingService struct {
+	services.MatchmakingService
+	playerID primitive.ObjectID
+}
+
+func (s *stubMatchmakingService) GetSeek(ctx context.Context, playerID primitive.ObjectID) (*services.SeekResponse, error) {
+	s.playerID = playerID
+	return &services.SeekResponse{Message: "Seek retrieved"}, nil
+}
+
+// TestRequireAuthPassesUserToHandlers checks that handlers find the user that
+// RequireAuth put in the request context
+func TestRequireAuthPassesUserToHandlers(t *testing.T) {
+	auth := &stubAuthService{sessionID: "session", userID: primitive.NewObjectID()}
+	seeks := &stubMatchmakingService{}
+	handler := NewAuthMiddleware(auth).RequireAuth(http.HandlerFunc(matchmaking.NewMatchmakingHandlers(seeks).GetSeekHandler))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/matchmaking/seek", nil)
+	req.AddCookie(&http.Cookie{Name: "session_id", Value: "session"})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if seeks.playerID != auth.userID {
+		t.Errorf("handler got user %s, want %s", seeks.playerID.Hex(), auth.userID.Hex())
+	}
+}
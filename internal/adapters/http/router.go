@@ -6,7 +6,11 @@ import (
 	"net/http"
 
 	"chess-backend/internal/adapters/http/auth"
+	"chess-backend/internal/adapters/http/challenge"
 	"chess-backend/internal/adapters/http/game"
+	"chess-backend/internal/adapters/http/matchmaking"
+	"chess-backend/internal/adapters/sse"
+	"chess-backend/internal/adapters/websocket"
 	"chess-backend/internal/ports/services"
 
 	"github.com/gorilla/mux"
@@ -14,14 +18,18 @@ import (
 
 // Server represents the HTTP server
 type Server struct {
-	router         *mux.Router
-	authHandler    *auth.Handler
-	gameHandler    *game.GameHandlers
-	authMiddleware *AuthMiddleware
+	router             *mux.Router
+	authHandler        *auth.Handler
+	gameHandler        *game.GameHandlers
+	socketHandler      *websocket.Handler
+	streamHandler      *sse.Handler
+	matchmakingHandler *matchmaking.MatchmakingHandlers
+	challengeHandler   *challenge.ChallengeHandlers
+	authMiddleware     *AuthMiddleware
 }
 
 // NewServer creates a new HTTP server
-func NewServer(authService services.AuthService, gameService services.GameService) *Server {
+func NewServer(authService services.AuthService, gameService services.GameService, matchmakingService services.MatchmakingService, challengeService services.ChallengeService, hub *websocket.Hub) *Server {
 	router := mux.NewRouter()
 
 	// Create handlers
@@ -30,15 +38,33 @@ func NewServer(authService services.AuthService, gameService services.GameServic
 
 	// Create game handler if gameService is provided
 	var gameHandler *game.GameHandlers
+	var socketHandler *websocket.Handler
+	var streamHandler *sse.Handler
 	if gameService != nil {
 		gameHandler = game.NewGameHandlers(gameService)
+		socketHandler = websocket.NewHandler(gameService, hub)
+		streamHandler = sse.NewHandler(gameService, hub)
+	}
+
+	var matchmakingHandler *matchmaking.MatchmakingHandlers
+	if matchmakingService != nil {
+		matchmakingHandler = matchmaking.NewMatchmakingHandlers(matchmakingService)
+	}
+
+	var challengeHandler *challenge.ChallengeHandlers
+	if challengeService != nil {
+		challengeHandler = challenge.NewChallengeHandlers(challengeService)
 	}
 
 	server := &Server{
-		router:         router,
-		authHandler:    authHandler,
-		gameHandler:    gameHandler,
-		authMiddleware: authMiddleware,
+		router:             router,
+		authHandler:        authHandler,
+		gameHandler:        gameHandler,
+		socketHandler:      socketHandler,
+		streamHandler:      streamHandler,
+		matchmakingHandler: matchmakingHandler,
+		challengeHandler:   challengeHandler,
+		authMiddleware:     authMiddleware,
 	}
 
 	// Setup routes
@@ -69,6 +95,27 @@ func (s *Server) setupRoutes() {
 		s.registerGameRoutes(gameRoutes)
 	}
 
+	// Protected matchmaking routes
+	if s.matchmakingHandler != nil {
+		matchmakingRoutes := api.PathPrefix("/matchmaking").Subrouter()
+		matchmakingRoutes.Use(s.authMiddleware.RequireAuth)
+		s.registerMatchmakingRoutes(matchmakingRoutes)
+	}
+
+	// Protected stream of the events addressed to the user
+	if s.streamHandler != nil {
+		eventRoutes := api.PathPrefix("/events").Subrouter()
+		eventRoutes.Use(s.authMiddleware.RequireAuth)
+		eventRoutes.HandleFunc("", s.streamHandler.UserEventsHandler).Methods("GET")
+	}
+
+	// Protected challenge routes
+	if s.challengeHandler != nil {
+		challengeRoutes := api.PathPrefix("/challenges").Subrouter()
+		challengeRoutes.Use(s.authMiddleware.RequireAuth)
+		s.registerChallengeRoutes(challengeRoutes)
+	}
+
 	// Serve static files (if needed)
 	s.router.PathPrefix("/").Handler(http.FileServer(http.Dir("./static/"))).Methods("GET")
 }
@@ -90,10 +137,24 @@ func (s *Server) registerGameRoutes(router *mux.Router) {
 	// Game management routes
 	router.HandleFunc("/create", s.gameHandler.CreateGameHandler).Methods("POST")
 	router.HandleFunc("/join/{gameId}", s.gameHandler.JoinGameHandler).Methods("POST")
+	router.HandleFunc("/rating-history", s.gameHandler.GetRatingHistoryHandler).Methods("GET") // Before /{gameId}, which would match it
 	router.HandleFunc("/{gameId}", s.gameHandler.GetGameHandler).Methods("GET")
+	router.HandleFunc("/{gameId}", s.gameHandler.CancelGameHandler).Methods("DELETE")
 	router.HandleFunc("/{gameId}/move", s.gameHandler.MoveHandler).Methods("POST")
 	router.HandleFunc("/{gameId}/resign", s.gameHandler.ResignGameHandler).Methods("POST")
+	router.HandleFunc("/{gameId}/abort", s.gameHandler.AbortGameHandler).Methods("POST")
+	router.HandleFunc("/{gameId}/claim-abandonment", s.gameHandler.ClaimAbandonmentHandler).Methods("POST")
+	router.HandleFunc("/{gameId}/claim-draw", s.gameHandler.ClaimDrawHandler).Methods("POST")
+	router.HandleFunc("/{gameId}/draw", s.gameHandler.OfferDrawHandler).Methods("POST")
+	router.HandleFunc("/{gameId}/draw/accept", s.gameHandler.AcceptDrawHandler).Methods("POST")
+	router.HandleFunc("/{gameId}/draw/decline", s.gameHandler.DeclineDrawHandler).Methods("POST")
+	router.HandleFunc("/{gameId}/takeback", s.gameHandler.RequestTakebackHandler).Methods("POST")
+	router.HandleFunc("/{gameId}/takeback/accept", s.gameHandler.AcceptTakebackHandler).Methods("POST")
+	router.HandleFunc("/{gameId}/takeback/decline", s.gameHandler.DeclineTakebackHandler).Methods("POST")
 	router.HandleFunc("/{gameId}/history", s.gameHandler.GetGameHistoryHandler).Methods("GET")
+	router.HandleFunc("/{gameId}/legal-moves", s.gameHandler.GetLegalMovesHandler).Methods("GET")
+	router.HandleFunc("/{gameId}/ws", s.socketHandler.GameSocketHandler).Methods("GET")
+	router.HandleFunc("/{gameId}/events", s.streamHandler.GameEventsHandler).Methods("GET")
 
 	// Game listing routes
 	router.HandleFunc("/my-games", s.gameHandler.ListPlayerGamesHandler).Methods("GET")
@@ -104,6 +165,23 @@ func (s *Server) registerGameRoutes(router *mux.Router) {
 	router.HandleFunc("/stats", s.gameHandler.GetPlayerStatsHandler).Methods("GET")
 }
 
+// registerMatchmakingRoutes registers all matchmaking routes
+func (s *Server) registerMatchmakingRoutes(router *mux.Router) {
+	router.HandleFunc("/seek", s.matchmakingHandler.SeekHandler).Methods("POST")
+	router.HandleFunc("/seek", s.matchmakingHandler.GetSeekHandler).Methods("GET")
+	router.HandleFunc("/seek", s.matchmakingHandler.CancelSeekHandler).Methods("DELETE")
+}
+
+// registerChallengeRoutes registers all challenge routes
+func (s *Server) registerChallengeRoutes(router *mux.Router) {
+	router.HandleFunc("", s.challengeHandler.CreateChallengeHandler).Methods("POST")
+	router.HandleFunc("", s.challengeHandler.ListChallengesHandler).Methods("GET")
+	router.HandleFunc("/{challengeId}", s.challengeHandler.GetChallengeHandler).Methods("GET")
+	router.HandleFunc("/{challengeId}/accept", s.challengeHandler.AcceptChallengeHandler).Methods("POST")
+	router.HandleFunc("/{challengeId}/decline", s.challengeHandler.DeclineChallengeHandler).Methods("POST")
+	router.HandleFunc("/{challengeId}/cancel", s.challengeHandler.CancelChallengeHandler).Methods("POST")
+}
+
 // Start starts the HTTP server on the specified address
 func (s *Server) Start(addr string) error {
 	return http.ListenAndServe(addr, s.router)
@@ -0,0 +1,120 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"chess-backend/internal/domain/challenge"
+	"chess-backend/internal/ports/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// challengeRepository implements the ChallengeRepository interface using MongoDB
+type challengeRepository struct {
+	collection *mongo.Collection
+}
+
+// NewChallengeRepository creates a new instance of ChallengeRepository
+func NewChallengeRepository(collection *mongo.Collection) repositories.ChallengeRepository {
+	return &challengeRepository{
+		collection: collection,
+	}
+}
+
+// Save inserts a new challenge
+func (r *challengeRepository) Save(ctx context.Context, c *challenge.Challenge) error {
+	if c == nil {
+		return errors.New("challenge cannot be nil")
+	}
+	if c.ID.IsZero() {
+		c.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, c)
+	return err
+}
+
+// FindByID retrieves a challenge by its ID
+func (r *challengeRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*challenge.Challenge, error) {
+	var c challenge.Challenge
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&c)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.New("challenge not found")
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+// FindPendingByUser retrieves the pending challenges a user sent or received, newest first
+func (r *challengeRepository) FindPendingByUser(ctx context.Context, userID primitive.ObjectID) ([]*challenge.Challenge, error) {
+	filter := bson.M{
+		"status": challenge.ChallengeStatusPending,
+		"$or": []bson.M{
+			{"challenger_id": userID},
+			{"challenged_id": userID},
+		},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	return r.find(ctx, filter, opts)
+}
+
+// FindExpired retrieves the pending challenges that expired before the given time
+func (r *challengeRepository) FindExpired(ctx context.Context, before time.Time) ([]*challenge.Challenge, error) {
+	filter := bson.M{
+		"status":     challenge.ChallengeStatusPending,
+		"expires_at": bson.M{"$lte": before},
+	}
+
+	return r.find(ctx, filter)
+}
+
+// Respond stores the answer to a challenge that is still pending in the collection
+func (r *challengeRepository) Respond(ctx context.Context, c *challenge.Challenge) error {
+	if c == nil {
+		return errors.New("challenge cannot be nil")
+	}
+
+	filter := bson.M{"_id": c.ID, "status": challenge.ChallengeStatusPending}
+	set := bson.M{
+		"status":       c.Status,
+		"responded_at": c.RespondedAt,
+	}
+	if !c.GameID.IsZero() {
+		set["game_id"] = c.GameID
+	}
+	update := bson.M{"$set": set}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("challenge is no longer pending")
+	}
+
+	return nil
+}
+
+// find retrieves the challenges matching a filter
+func (r *challengeRepository) find(ctx context.Context, filter bson.M, opts ...*options.FindOptions) ([]*challenge.Challenge, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var challenges []*challenge.Challenge
+	if err := cursor.All(ctx, &challenges); err != nil {
+		return nil, err
+	}
+	return challenges, nil
+}
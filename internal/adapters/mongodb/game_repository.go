@@ -44,7 +44,11 @@ func (r *gameRepository) Save(ctx context.Context, g *game.Game) error {
 	// If ID is empty, this is a new game
 	if g.ID.IsZero() {
 		g.ID = primitive.NewObjectID()
-		result, err := r.collection.InsertOne(ctx, g)
+		doc, err := gameDocument(g)
+		if err != nil {
+			return err
+		}
+		result, err := r.collection.InsertOne(ctx, doc)
 		if err != nil {
 			return err
 		}
@@ -53,9 +57,13 @@ func (r *gameRepository) Save(ctx context.Context, g *game.Game) error {
 	}
 
 	// Update existing game
+	doc, err := gameDocument(g)
+	if err != nil {
+		return err
+	}
 	filter := bson.M{"_id": g.ID}
-	update := bson.M{"$set": g}
-	_, err := r.collection.UpdateOne(ctx, filter, update)
+	update := bson.M{"$set": doc}
+	_, err = r.collection.UpdateOne(ctx, filter, update)
 	return err
 }
 
@@ -82,11 +90,47 @@ func (r *gameRepository) Update(ctx context.Context, g *game.Game) error {
 		return errors.New("game ID cannot be empty")
 	}
 
+	// Only update the game if nobody else has saved it since it was loaded
+	filter := bson.M{"_id": g.ID, "version": g.Version}
+	if g.Version == 0 {
+		// Games saved before versions were introduced have no version field
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	}
+
 	g.UpdatedAt = time.Now()
-	filter := bson.M{"_id": g.ID}
-	update := bson.M{"$set": g}
-	_, err := r.collection.UpdateOne(ctx, filter, update)
-	return err
+	doc, err := gameDocument(g)
+	if err != nil {
+		return err
+	}
+	doc["version"] = g.Version + 1
+	update := bson.M{"$set": doc}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return repositories.ErrGameModified
+	}
+
+	g.Version++
+	return nil
+}
+
+// gameDocument converts a game to the document stored in the collection. The move
+// deadline is stored with it, so the games that ran out of time can be queried.
+func gameDocument(g *game.Game) (bson.M, error) {
+	data, err := bson.Marshal(g)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc bson.M
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	doc["deadline"] = g.MoveDeadline()
+	return doc, nil
 }
 
 // Delete removes a game from the repository
@@ -96,6 +140,29 @@ func (r *gameRepository) Delete(ctx context.Context, id primitive.ObjectID) erro
 	return err
 }
 
+// DeleteWaiting removes a game that is still waiting for an opponent, unless it was
+// saved since it was loaded
+func (r *gameRepository) DeleteWaiting(ctx context.Context, g *game.Game) error {
+	if g == nil {
+		return errors.New("game cannot be nil")
+	}
+
+	filter := bson.M{"_id": g.ID, "status": game.GameStatusWaiting, "version": g.Version}
+	if g.Version == 0 {
+		// Games saved before versions were introduced have no version field
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	}
+
+	result, err := r.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return repositories.ErrGameModified
+	}
+	return nil
+}
+
 // FindByPlayerID retrieves all games for a specific player
 func (r *gameRepository) FindByPlayerID(ctx context.Context, playerID primitive.ObjectID) ([]*game.Game, error) {
 	filter := bson.M{
@@ -128,9 +195,109 @@ func (r *gameRepository) FindActiveGames(ctx context.Context) ([]*game.Game, err
 	return r.FindByStatus(ctx, game.GameStatusActive)
 }
 
-// FindWaitingGames retrieves all games waiting for players
+// FindDueGames retrieves the active games whose side to move ran out of time by now
+func (r *gameRepository) FindDueGames(ctx context.Context, now time.Time) ([]*game.Game, error) {
+	filter := bson.M{
+		"status":   game.GameStatusActive,
+		"deadline": bson.M{"$lte": now},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var games []*game.Game
+	for cursor.Next(ctx) {
+		var g game.Game
+		if err := cursor.Decode(&g); err != nil {
+			return nil, err
+		}
+		games = append(games, &g)
+	}
+
+	return games, cursor.Err()
+}
+
+// FindUnplayedGames retrieves the active games in which both players have not moved yet
+func (r *gameRepository) FindUnplayedGames(ctx context.Context) ([]*game.Game, error) {
+	filter := bson.M{
+		"status":  game.GameStatusActive,
+		"moves.1": bson.M{"$exists": false},
+		"max_ply": bson.M{"$not": bson.M{"$gte": 2}}, // Also matches games saved without it
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var games []*game.Game
+	for cursor.Next(ctx) {
+		var g game.Game
+		if err := cursor.Decode(&g); err != nil {
+			return nil, err
+		}
+		games = append(games, &g)
+	}
+
+	return games, cursor.Err()
+}
+
+// FindUnratedGames retrieves the decided rated games that finished before the given
+// time and still have no rating changes
+func (r *gameRepository) FindUnratedGames(ctx context.Context, finishedBefore time.Time) ([]*game.Game, error) {
+	filter := bson.M{
+		"rated":          true,
+		"finished_at":    bson.M{"$lte": finishedBefore},
+		"status":         bson.M{"$in": bson.A{game.GameStatusFinished, game.GameStatusAbandoned}},
+		"result":         bson.M{"$in": bson.A{game.GameResultWhiteWins, game.GameResultBlackWins, game.GameResultDraw}},
+		"rating_changes": bson.M{"$exists": false},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var games []*game.Game
+	for cursor.Next(ctx) {
+		var g game.Game
+		if err := cursor.Decode(&g); err != nil {
+			return nil, err
+		}
+		games = append(games, &g)
+	}
+
+	return games, cursor.Err()
+}
+
+// FindWaitingGames retrieves all public games waiting for players
 func (r *gameRepository) FindWaitingGames(ctx context.Context) ([]*game.Game, error) {
-	return r.FindByStatus(ctx, game.GameStatusWaiting)
+	filter := bson.M{
+		"status":  game.GameStatusWaiting,
+		"private": bson.M{"$ne": true},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var games []*game.Game
+	for cursor.Next(ctx) {
+		var g game.Game
+		if err := cursor.Decode(&g); err != nil {
+			return nil, err
+		}
+		games = append(games, &g)
+	}
+
+	return games, cursor.Err()
 }
 
 // FindByStatus retrieves games by their status
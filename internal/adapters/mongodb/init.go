@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -91,11 +92,36 @@ func CreateIndexes(client *mongo.Client, databaseName string) error {
 		{
 			Keys: map[string]interface{}{"created_at": -1},
 		},
+		{
+			// Active games that ran out of time, found by the flag sweepers
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "deadline", Value: 1}},
+		},
+		{
+			// Rated games that still have to be rated, found by the rating worker
+			Keys: bson.D{{Key: "rated", Value: 1}, {Key: "finished_at", Value: 1}},
+		},
 	}
 	_, err = gamesCollection.Indexes().CreateMany(ctx, playerIndexes)
 	if err != nil {
 		return fmt.Errorf("failed to create game indexes: %w", err)
 	}
 
+	// Create indexes for rating history collection
+	ratingHistoryCollection := db.Collection("rating_history")
+	ratingHistoryIndexes := []mongo.IndexModel{
+		{
+			// A game changes each player's rating once
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "game_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "category", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	}
+	_, err = ratingHistoryCollection.Indexes().CreateMany(ctx, ratingHistoryIndexes)
+	if err != nil {
+		return fmt.Errorf("failed to create rating history indexes: %w", err)
+	}
+
 	return nil
 }
\ No newline at end of file
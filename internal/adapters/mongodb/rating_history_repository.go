@@ -0,0 +1,84 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"chess-backend/internal/domain/rating"
+	"chess-backend/internal/ports/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ratingHistoryRepository implements the RatingHistoryRepository interface using MongoDB
+type ratingHistoryRepository struct {
+	collection *mongo.Collection
+}
+
+// NewRatingHistoryRepository creates a new instance of RatingHistoryRepository
+func NewRatingHistoryRepository(collection *mongo.Collection) repositories.RatingHistoryRepository {
+	return &ratingHistoryRepository{
+		collection: collection,
+	}
+}
+
+// Save stores a rating history entry. A game is recorded once per user, so saving
+// the entry again when the rating is retried replaces the earlier one.
+func (r *ratingHistoryRepository) Save(ctx context.Context, entry *rating.HistoryEntry) error {
+	if entry == nil {
+		return errors.New("rating history entry cannot be nil")
+	}
+	if entry.ID.IsZero() {
+		entry.ID = primitive.NewObjectID()
+	}
+
+	filter := bson.M{"user_id": entry.UserID, "game_id": entry.GameID}
+	update := bson.M{
+		"$set": bson.M{
+			"category":   entry.Category,
+			"before":     entry.Before,
+			"after":      entry.After,
+			"created_at": entry.CreatedAt,
+		},
+		"$setOnInsert": bson.M{"_id": entry.ID},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// FindByGame retrieves a user's entry for a game
+func (r *ratingHistoryRepository) FindByGame(ctx context.Context, userID, gameID primitive.ObjectID) (*rating.HistoryEntry, error) {
+	var entry rating.HistoryEntry
+	err := r.collection.FindOne(ctx, bson.M{"user_id": userID, "game_id": gameID}).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("rating history entry not found")
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// FindByUserID retrieves the most recent entries of a user in a category
+func (r *ratingHistoryRepository) FindByUserID(ctx context.Context, userID primitive.ObjectID, category rating.Category, limit int) ([]*rating.HistoryEntry, error) {
+	filter := bson.M{"user_id": userID, "category": category}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []*rating.HistoryEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"time"
 
+	"chess-backend/internal/domain/rating"
 	"chess-backend/internal/domain/user"
 	"chess-backend/internal/ports/repositories"
 
@@ -138,6 +139,44 @@ func (r *userRepository) Update(ctx context.Context, user *user.User) error {
 	return nil
 }
 
+// UpdateRating sets a single rating category so concurrent games in other
+// categories do not overwrite each other. The games counter of the rating acts as
+// its version: the update only applies while the stored rating is still the one
+// before the game, and the game is remembered so it is never applied twice.
+func (r *userRepository) UpdateRating(ctx context.Context, id primitive.ObjectID, category rating.Category, before, after rating.Rating, gameID primitive.ObjectID) error {
+	key := "ratings." + string(category)
+	filter := bson.M{
+		"_id":          id,
+		key + ".games": before.Games,
+		"rated_games":  bson.M{"$ne": gameID},
+	}
+	if before.Games == 0 {
+		// Users without games in the category have no stored rating yet
+		filter[key+".games"] = bson.M{"$in": bson.A{0, nil}}
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			key:          after,
+			"updated_at": time.Now(),
+		},
+		"$push": bson.M{
+			"rated_games": bson.M{"$each": bson.A{gameID}, "$slice": -user.RatedGamesKept},
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return repositories.ErrRatingModified
+	}
+
+	return nil
+}
+
 // Delete removes a user from the database
 func (r *userRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
 	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
@@ -0,0 +1,79 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"chess-backend/internal/domain/game"
+	"chess-backend/internal/ports/events"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// gameEventPublisher implements the GameEventPublisher interface using Redis pub/sub.
+// Events are published on one channel per game, or per user for events addressed to a
+// single user, and received through pattern subscriptions.
+type gameEventPublisher struct {
+	client     *redis.Client
+	prefix     string
+	userPrefix string
+}
+
+// NewGameEventPublisher creates a new Redis game event publisher
+func NewGameEventPublisher(client *redis.Client) events.GameEventPublisher {
+	return &gameEventPublisher{
+		client:     client,
+		prefix:     "game_events:",
+		userPrefix: "user_events:",
+	}
+}
+
+// Publish sends an event on the channel of its game, or of its user if it has one
+func (p *gameEventPublisher) Publish(ctx context.Context, event game.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal game event: %w", err)
+	}
+
+	channel := p.prefix + event.GameID.Hex()
+	if event.UserID != nil {
+		channel = p.userPrefix + event.UserID.Hex()
+	}
+	if err := p.client.Publish(ctx, channel, data).Err(); err != nil {
+		return fmt.Errorf("failed to publish game event: %w", err)
+	}
+
+	return nil
+}
+
+// Subscribe receives the events of all games until ctx is cancelled.
+// The client reconnects and resubscribes on its own if the connection drops.
+func (p *gameEventPublisher) Subscribe(ctx context.Context, handler func(game.Event)) error {
+	pubsub := p.client.PSubscribe(ctx, p.prefix+"*", p.userPrefix+"*")
+	defer pubsub.Close()
+
+	// Wait for the subscription to be confirmed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return fmt.Errorf("failed to subscribe to game events: %w", err)
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			var event game.Event
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				log.Printf("Failed to decode game event from %s: %v", msg.Channel, err)
+				continue
+			}
+			handler(event)
+		}
+	}
+}
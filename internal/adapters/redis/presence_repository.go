@@ -0,0 +1,63 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"chess-backend/internal/ports/repositories"
+
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// presenceTTL is how long the presence of a game is kept after its last update
+const presenceTTL = 24 * time.Hour
+
+// presenceRepository implements the PresenceRepository interface using Redis.
+// Each game has a hash of player IDs to the Unix time in milliseconds they were last seen.
+type presenceRepository struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewPresenceRepository creates a new Redis presence repository
+func NewPresenceRepository(client *redis.Client) repositories.PresenceRepository {
+	return &presenceRepository{
+		client: client,
+		prefix: "presence:",
+	}
+}
+
+// getKey returns the Redis key for the presence of a game
+func (r *presenceRepository) getKey(gameID primitive.ObjectID) string {
+	return r.prefix + gameID.Hex()
+}
+
+// Touch records when a player was last seen
+func (r *presenceRepository) Touch(ctx context.Context, gameID primitive.ObjectID, playerID primitive.ObjectID, at time.Time) error {
+	key := r.getKey(gameID)
+
+	pipe := r.client.Pipeline()
+	pipe.HSet(ctx, key, playerID.Hex(), at.UnixMilli())
+	pipe.Expire(ctx, key, presenceTTL)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to save presence: %w", err)
+	}
+
+	return nil
+}
+
+// LastSeen returns when a player was last seen
+func (r *presenceRepository) LastSeen(ctx context.Context, gameID primitive.ObjectID, playerID primitive.ObjectID) (time.Time, error) {
+	ms, err := r.client.HGet(ctx, r.getKey(gameID), playerID.Hex()).Int64()
+	if err != nil {
+		if err == redis.Nil {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("failed to get presence: %w", err)
+	}
+
+	return time.UnixMilli(ms), nil
+}
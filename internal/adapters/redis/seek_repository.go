@@ -0,0 +1,189 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"chess-backend/internal/domain/matchmaking"
+	"chess-backend/internal/ports/repositories"
+
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// seekTTL is how long a seek is kept, both while searching and after a match so the
+// player can pick up the game
+const seekTTL = 10 * time.Minute
+
+// seekRepository implements the SeekRepository interface using Redis.
+// Seeks are stored as JSON per player; each queue is a sorted set of player IDs
+// scored by rating, and a set keeps track of the queues in use.
+type seekRepository struct {
+	client      *redis.Client
+	prefix      string
+	queuePrefix string
+	queuesKey   string
+}
+
+// NewSeekRepository creates a new Redis seek repository
+func NewSeekRepository(client *redis.Client) repositories.SeekRepository {
+	return &seekRepository{
+		client:      client,
+		prefix:      "seek:",
+		queuePrefix: "seek_queue:",
+		queuesKey:   "seek_queues",
+	}
+}
+
+// getKey returns the Redis key for the seek of a player
+func (r *seekRepository) getKey(playerID primitive.ObjectID) string {
+	return r.prefix + playerID.Hex()
+}
+
+// getQueueKey returns the Redis key for a queue
+func (r *seekRepository) getQueueKey(queueKey string) string {
+	return r.queuePrefix + queueKey
+}
+
+// Save stores a seek with TTL and queues it while it is searching
+func (r *seekRepository) Save(ctx context.Context, seek *matchmaking.Seek) error {
+	if seek == nil {
+		return errors.New("seek cannot be nil")
+	}
+
+	data, err := json.Marshal(seek)
+	if err != nil {
+		return fmt.Errorf("failed to marshal seek: %w", err)
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.Set(ctx, r.getKey(seek.PlayerID), data, seekTTL)
+	if seek.Status == matchmaking.SeekStatusSearching {
+		pipe.ZAdd(ctx, r.getQueueKey(seek.QueueKey()), redis.Z{
+			Score:  float64(seek.Rating),
+			Member: seek.PlayerID.Hex(),
+		})
+		pipe.SAdd(ctx, r.queuesKey, seek.QueueKey())
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to save seek: %w", err)
+	}
+
+	return nil
+}
+
+// FindByPlayerID retrieves the seek of a player
+func (r *seekRepository) FindByPlayerID(ctx context.Context, playerID primitive.ObjectID) (*matchmaking.Seek, error) {
+	data, err := r.client.Get(ctx, r.getKey(playerID)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, errors.New("seek not found")
+		}
+		return nil, fmt.Errorf("failed to get seek: %w", err)
+	}
+
+	var seek matchmaking.Seek
+	if err := json.Unmarshal([]byte(data), &seek); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal seek: %w", err)
+	}
+
+	return &seek, nil
+}
+
+// FindInQueue retrieves the searching seeks of a queue ordered by rating.
+// Queue entries whose seek has expired are removed.
+func (r *seekRepository) FindInQueue(ctx context.Context, queueKey string) ([]*matchmaking.Seek, error) {
+	key := r.getQueueKey(queueKey)
+
+	playerIDs, err := r.client.ZRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queue: %w", err)
+	}
+	if len(playerIDs) == 0 {
+		return []*matchmaking.Seek{}, nil
+	}
+
+	keys := make([]string, len(playerIDs))
+	for i, id := range playerIDs {
+		keys[i] = r.prefix + id
+	}
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get seeks: %w", err)
+	}
+
+	seeks := make([]*matchmaking.Seek, 0, len(values))
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			// The seek expired, drop it from the queue
+			r.client.ZRem(ctx, key, playerIDs[i])
+			continue
+		}
+
+		var seek matchmaking.Seek
+		if err := json.Unmarshal([]byte(data), &seek); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal seek: %w", err)
+		}
+		if seek.Status == matchmaking.SeekStatusSearching {
+			seeks = append(seeks, &seek)
+		}
+	}
+
+	return seeks, nil
+}
+
+// Queues returns the keys of the queues that have seeks waiting, forgetting empty ones
+func (r *seekRepository) Queues(ctx context.Context) ([]string, error) {
+	queueKeys, err := r.client.SMembers(ctx, r.queuesKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queues: %w", err)
+	}
+
+	active := make([]string, 0, len(queueKeys))
+	for _, queueKey := range queueKeys {
+		size, err := r.client.ZCard(ctx, r.getQueueKey(queueKey)).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get queue size: %w", err)
+		}
+		if size == 0 {
+			r.client.SRem(ctx, r.queuesKey, queueKey)
+			continue
+		}
+		active = append(active, queueKey)
+	}
+
+	return active, nil
+}
+
+// Claim removes a seek from its queue, reporting whether this call removed it
+func (r *seekRepository) Claim(ctx context.Context, seek *matchmaking.Seek) (bool, error) {
+	removed, err := r.client.ZRem(ctx, r.getQueueKey(seek.QueueKey()), seek.PlayerID.Hex()).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim seek: %w", err)
+	}
+
+	return removed == 1, nil
+}
+
+// Delete removes the seek of a player and takes it out of its queue
+func (r *seekRepository) Delete(ctx context.Context, playerID primitive.ObjectID) error {
+	seek, err := r.FindByPlayerID(ctx, playerID)
+	if err != nil {
+		return err
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.ZRem(ctx, r.getQueueKey(seek.QueueKey()), playerID.Hex())
+	pipe.Del(ctx, r.getKey(playerID))
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to delete seek: %w", err)
+	}
+
+	return nil
+}
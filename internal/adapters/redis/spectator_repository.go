@@ -0,0 +1,87 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"chess-backend/internal/ports/repositories"
+
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// spectatorRepository implements the SpectatorRepository interface using Redis.
+// Each game has a sorted set of "userID:connectionID" members scored by the time their
+// entry expires, so a user watching over several connections is counted once and
+// stays counted until the last of them is closed.
+type spectatorRepository struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewSpectatorRepository creates a new Redis spectator repository
+func NewSpectatorRepository(client *redis.Client) repositories.SpectatorRepository {
+	return &spectatorRepository{
+		client: client,
+		prefix: "spectators:",
+	}
+}
+
+// getKey returns the Redis key for the spectators of a game
+func (r *spectatorRepository) getKey(gameID primitive.ObjectID) string {
+	return r.prefix + gameID.Hex()
+}
+
+// member returns the set member of a spectator's connection
+func member(userID primitive.ObjectID, connectionID string) string {
+	return userID.Hex() + ":" + connectionID
+}
+
+// Touch adds or refreshes a spectator entry
+func (r *spectatorRepository) Touch(ctx context.Context, gameID primitive.ObjectID, userID primitive.ObjectID, connectionID string, until time.Time) error {
+	key := r.getKey(gameID)
+
+	pipe := r.client.Pipeline()
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(until.Unix()), Member: member(userID, connectionID)})
+	// The set disappears once its last entry has expired
+	pipe.ExpireAt(ctx, key, until)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to save spectator: %w", err)
+	}
+
+	return nil
+}
+
+// Remove deletes the entry of a spectator's connection
+func (r *spectatorRepository) Remove(ctx context.Context, gameID primitive.ObjectID, userID primitive.ObjectID, connectionID string) error {
+	if err := r.client.ZRem(ctx, r.getKey(gameID), member(userID, connectionID)).Err(); err != nil {
+		return fmt.Errorf("failed to remove spectator: %w", err)
+	}
+
+	return nil
+}
+
+// Count drops expired entries and returns the number of users with a connection left
+func (r *spectatorRepository) Count(ctx context.Context, gameID primitive.ObjectID) (int64, error) {
+	key := r.getKey(gameID)
+
+	pipe := r.client.Pipeline()
+	pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(time.Now().Unix(), 10))
+	members := pipe.ZRange(ctx, key, 0, -1)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to count spectators: %w", err)
+	}
+
+	users := make(map[string]struct{}, len(members.Val()))
+	for _, m := range members.Val() {
+		userID, _, _ := strings.Cut(m, ":")
+		users[userID] = struct{}{}
+	}
+
+	return int64(len(users)), nil
+}
@@ -0,0 +1,267 @@
+// Package sse streams real-time game updates as Server-Sent Events, for clients that
+// cannot keep a WebSocket open.
+// This is part of the Adapters layer in Hexagonal Architecture.
+package sse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"chess-backend/internal/adapters/http/authctx"
+	"chess-backend/internal/domain/game"
+	"chess-backend/internal/ports/services"
+	"chess-backend/internal/utils"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// keepAliveInterval is how often a comment is sent so proxies keep idle streams open
+const keepAliveInterval = 30 * time.Second
+
+// EventSource delivers the live events of a game
+type EventSource interface {
+	// Subscribe returns a channel receiving the events of a game and a function to
+	// stop receiving them. The channel is closed when the subscriber falls behind.
+	Subscribe(gameID primitive.ObjectID) (<-chan game.Event, func())
+
+	// SubscribeUser is like Subscribe for the events addressed to a single user
+	SubscribeUser(userID primitive.ObjectID) (<-chan game.Event, func())
+}
+
+// Handler streams game events to players and spectators
+type Handler struct {
+	gameService services.GameService
+	source      EventSource
+}
+
+// NewHandler creates a new SSE handler
+func NewHandler(gameService services.GameService, source EventSource) *Handler {
+	return &Handler{
+		gameService: gameService,
+		source:      source,
+	}
+}
+
+// GameEventsHandler handles GET /api/game/{gameId}/events.
+// Every event carries the number of moves played as its id. A client reconnecting
+// with Last-Event-ID first receives the moves it missed, then the current state.
+func (h *Handler) GameEventsHandler(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context (set by auth middleware)
+	userID, ok := authctx.UserIDFromContext(r.Context())
+	if !ok {
+		utils.Response.WriteUnauthorized(w, "User not authenticated")
+		return
+	}
+
+	gameID, err := primitive.ObjectIDFromHex(mux.Vars(r)["gameId"])
+	if err != nil {
+		utils.Response.WriteBadRequest(w, "Invalid game ID format")
+		return
+	}
+
+	lastPly := -1
+	if lastEventID := r.Header.Get("Last-Event-ID"); lastEventID != "" {
+		lastPly, err = strconv.Atoi(lastEventID)
+		if err != nil || lastPly < 0 {
+			utils.Response.WriteBadRequest(w, "Invalid Last-Event-ID")
+			return
+		}
+	}
+
+	// Subscribe before loading the game so no event falls between the two
+	events, cancel := h.source.Subscribe(gameID)
+	defer cancel()
+
+	// Players may always listen, other users only if the game allows spectators
+	gameEntity, err := h.gameService.GetGame(r.Context(), gameID, userID)
+	if err != nil {
+		utils.Response.WriteBadRequest(w, err.Error())
+		return
+	}
+
+	var backlog []game.Event
+	if lastPly >= 0 {
+		backlog, err = gameEntity.MoveEventsSince(lastPly)
+		if err != nil {
+			utils.Response.WriteInternalServerError(w, err.Error())
+			return
+		}
+	}
+	backlog = append(backlog, game.NewEvent(game.EventGameState, gameEntity))
+
+	rc, ok := openStream(w)
+	if !ok {
+		return
+	}
+
+	sentPly := len(gameEntity.Moves)
+	for _, event := range backlog {
+		if err := writeEvent(w, event); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	// Spectators stay counted and players stay present while the stream is open
+	var heartbeat, presence <-chan time.Time
+	connectionID := primitive.NewObjectID().Hex()
+	if !gameEntity.IsPlayerInGame(userID) {
+		h.watch(r, gameID, userID, connectionID)
+		defer h.unwatch(r, gameID, userID, connectionID)
+
+		ticker := time.NewTicker(services.SpectatorHeartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	} else {
+		ticker := time.NewTicker(services.PresenceHeartbeat)
+		defer ticker.Stop()
+		presence = ticker.C
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				// Fell behind; the client reconnects with Last-Event-ID and catches up
+				return
+			}
+			if event.Type == game.EventMoveMade && event.Ply <= sentPly {
+				// Already sent as part of the game loaded above
+				continue
+			}
+			if event.Type == game.EventTakeback && event.Ply < sentPly {
+				// Moves after the takeback are new even if their plies were sent before
+				sentPly = event.Ply
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case <-heartbeat:
+			h.watch(r, gameID, userID, connectionID)
+			continue
+		case <-presence:
+			if err := h.gameService.RecordPresence(r.Context(), gameID, userID); err != nil {
+				log.Printf("Failed to record presence in game %s: %v", gameID.Hex(), err)
+			}
+			continue
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// UserEventsHandler handles GET /api/events, streaming the events addressed to the
+// authenticated user, such as being matched into a new game
+func (h *Handler) UserEventsHandler(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context (set by auth middleware)
+	userID, ok := authctx.UserIDFromContext(r.Context())
+	if !ok {
+		utils.Response.WriteUnauthorized(w, "User not authenticated")
+		return
+	}
+
+	events, cancel := h.source.SubscribeUser(userID)
+	defer cancel()
+
+	rc, ok := openStream(w)
+	if !ok {
+		return
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				// Fell behind; the client reconnects and checks its seeks and challenges
+				return
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// openStream starts an event stream response, which outlives the server's write
+// timeout. It reports false after writing an error response instead.
+func openStream(w http.ResponseWriter) (*http.ResponseController, bool) {
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		utils.Response.WriteInternalServerError(w, "Streaming is not supported")
+		return nil, false
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	return rc, true
+}
+
+// watch counts a spectator's stream as watching the game
+func (h *Handler) watch(r *http.Request, gameID, userID primitive.ObjectID, connectionID string) {
+	if err := h.gameService.WatchGame(r.Context(), gameID, userID, connectionID); err != nil {
+		log.Printf("Failed to record spectator of game %s: %v", gameID.Hex(), err)
+	}
+}
+
+// unwatch stops counting a spectator once the stream is closed
+func (h *Handler) unwatch(r *http.Request, gameID, userID primitive.ObjectID, connectionID string) {
+	// The request context is already cancelled when the client goes away
+	ctx := context.WithoutCancel(r.Context())
+	if err := h.gameService.UnwatchGame(ctx, gameID, userID, connectionID); err != nil {
+		log.Printf("Failed to remove spectator of game %s: %v", gameID.Hex(), err)
+	}
+}
+
+// writeEvent writes a single event in the SSE wire format. Only events that change the
+// move list carry an id, the number of moves after them, so the Last-Event-ID of a
+// reconnecting client always names the last move it has seen. Other events leave the
+// last id as it was.
+func writeEvent(w http.ResponseWriter, event game.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to encode %s event for game %s: %v", event.Type, event.GameID.Hex(), err)
+		return nil
+	}
+	if event.Type == game.EventMoveMade || event.Type == game.EventTakeback {
+		_, err = fmt.Fprintf(w, "id: %d\ndata: %s\n\n", event.Ply, data)
+	} else {
+		_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+	}
+	return err
+}
@@ -0,0 +1,106 @@
+package websocket
+
+import (
+	"sync"
+	"time"
+
+	"chess-backend/internal/domain/game"
+
+	gorilla "github.com/gorilla/websocket"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	writeWait      = 10 * time.Second    // Time allowed to write a message
+	pongWait       = 60 * time.Second    // Time allowed to read the next pong
+	pingPeriod     = (pongWait * 9) / 10 // Must be shorter than pongWait
+	maxMessageSize = 4096                // Largest message accepted from a client
+	sendBufferSize = 32                  // Messages queued per client before it is dropped
+)
+
+// client is a single socket connected to a game
+type client struct {
+	conn      *gorilla.Conn
+	id        string // Tells apart the connections of a user
+	gameID    primitive.ObjectID
+	userID    primitive.ObjectID
+	spectator bool // Spectators receive events but cannot play moves
+	send      chan []byte
+
+	mu     sync.Mutex // Guards closing send against concurrent enqueues
+	closed bool
+}
+
+// newClient wraps an upgraded connection
+func newClient(conn *gorilla.Conn, gameID, userID primitive.ObjectID, spectator bool) *client {
+	return &client{
+		conn:      conn,
+		id:        primitive.NewObjectID().Hex(),
+		gameID:    gameID,
+		userID:    userID,
+		spectator: spectator,
+		send:      make(chan []byte, sendBufferSize),
+	}
+}
+
+// deliver queues an encoded game event for the client
+func (c *client) deliver(_ game.Event, data []byte) {
+	c.enqueue(data)
+}
+
+// enqueue queues a message for the client. A client that cannot keep up is
+// disconnected rather than blocking the other sockets of the game.
+func (c *client) enqueue(data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return
+	}
+	select {
+	case c.send <- data:
+	default:
+		c.closed = true
+		close(c.send)
+	}
+}
+
+// close stops the write pump, which closes the connection
+func (c *client) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.closed {
+		c.closed = true
+		close(c.send)
+	}
+}
+
+// writePump writes queued messages and keeps the connection alive with pings.
+// It owns all writes to the connection.
+func (c *client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case data, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				c.conn.WriteMessage(gorilla.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteMessage(gorilla.TextMessage, data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(gorilla.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"chess-backend/internal/adapters/http/authctx"
+	"chess-backend/internal/domain/game"
+	"chess-backend/internal/ports/services"
+	"chess-backend/internal/utils"
+
+	"github.com/gorilla/mux"
+	gorilla "github.com/gorilla/websocket"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// clientMessage is a message sent by a player over the socket
+type clientMessage struct {
+	Type      string `json:"type"` // Only "move" is supported
+	Move      string `json:"move"` // UCI or SAN, or use From/To
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Promotion string `json:"promotion"`
+}
+
+// errorMessage reports a rejected client message to the sender only
+type errorMessage struct {
+	Type    string `json:"type"` // Always "error"
+	Message string `json:"message"`
+}
+
+// Handler upgrades game requests to WebSocket connections. Connected players and
+// spectators receive every event of the game; players can also play moves over the socket.
+type Handler struct {
+	gameService services.GameService
+	hub         *Hub
+	upgrader    gorilla.Upgrader
+}
+
+// NewHandler creates a new WebSocket handler. The upgrader only accepts same-origin
+// requests, since the socket is authenticated with the session cookie.
+func NewHandler(gameService services.GameService, hub *Hub) *Handler {
+	return &Handler{
+		gameService: gameService,
+		hub:         hub,
+		upgrader: gorilla.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+		},
+	}
+}
+
+// GameSocketHandler handles GET /api/game/{gameId}/ws
+func (h *Handler) GameSocketHandler(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context (set by auth middleware)
+	userID, ok := authctx.UserIDFromContext(r.Context())
+	if !ok {
+		utils.Response.WriteUnauthorized(w, "User not authenticated")
+		return
+	}
+
+	gameID, err := primitive.ObjectIDFromHex(mux.Vars(r)["gameId"])
+	if err != nil {
+		utils.Response.WriteBadRequest(w, "Invalid game ID format")
+		return
+	}
+
+	// Players may always connect, other users only if the game allows spectators
+	gameEntity, err := h.gameService.GetGame(r.Context(), gameID, userID)
+	if err != nil {
+		utils.Response.WriteBadRequest(w, err.Error())
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has already written an error response
+		log.Printf("WebSocket upgrade failed for game %s: %v", gameID.Hex(), err)
+		return
+	}
+
+	c := newClient(conn, gameID, userID, !gameEntity.IsPlayerInGame(userID))
+	h.hub.register(gameID, c)
+	defer h.hub.unregister(gameID, c)
+	defer c.close()
+
+	if c.spectator {
+		h.watch(r.Context(), c)
+		go h.keepWatching(r.Context(), c)
+		defer h.unwatch(r.Context(), c)
+	} else {
+		go h.keepPresent(r.Context(), c)
+	}
+
+	go c.writePump()
+	h.send(c, game.NewEvent(game.EventGameState, gameEntity))
+	h.readPump(r, c)
+}
+
+// readPump reads messages from the client until the connection is closed
+func (h *Handler) readPump(r *http.Request, c *client) {
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			if gorilla.IsUnexpectedCloseError(err, gorilla.CloseGoingAway, gorilla.CloseNormalClosure) {
+				log.Printf("WebSocket for game %s closed unexpectedly: %v", c.gameID.Hex(), err)
+			}
+			return
+		}
+
+		var msg clientMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			h.sendError(c, "Invalid message")
+			continue
+		}
+
+		switch {
+		case msg.Type == "move" && c.spectator:
+			h.sendError(c, "Spectators cannot make moves")
+		case msg.Type == "move":
+			h.handleMove(r, c, msg)
+		default:
+			h.sendError(c, "Unknown message type")
+		}
+	}
+}
+
+// handleMove plays a move sent over the socket. The resulting events reach the
+// clients through the game event publisher.
+func (h *Handler) handleMove(r *http.Request, c *client, msg clientMessage) {
+	_, err := h.gameService.MakeMove(r.Context(), services.MakeMoveRequest{
+		GameID:    c.gameID,
+		PlayerID:  c.userID,
+		Move:      msg.Move,
+		From:      msg.From,
+		To:        msg.To,
+		Promotion: msg.Promotion,
+	})
+	if err != nil {
+		h.sendError(c, err.Error())
+	}
+}
+
+// watch counts a spectator as watching the game
+func (h *Handler) watch(ctx context.Context, c *client) {
+	if err := h.gameService.WatchGame(ctx, c.gameID, c.userID, c.id); err != nil {
+		log.Printf("Failed to record spectator of game %s: %v", c.gameID.Hex(), err)
+	}
+}
+
+// keepWatching renews a spectator's entry until the connection is closed
+func (h *Handler) keepWatching(ctx context.Context, c *client) {
+	ticker := time.NewTicker(services.SpectatorHeartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.watch(ctx, c)
+		}
+	}
+}
+
+// keepPresent records a player as connected until the connection is closed, so
+// their opponent cannot claim the game as abandoned
+func (h *Handler) keepPresent(ctx context.Context, c *client) {
+	ticker := time.NewTicker(services.PresenceHeartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := h.gameService.RecordPresence(ctx, c.gameID, c.userID); err != nil {
+				log.Printf("Failed to record presence in game %s: %v", c.gameID.Hex(), err)
+			}
+		}
+	}
+}
+
+// unwatch stops counting a spectator once the connection is closed
+func (h *Handler) unwatch(ctx context.Context, c *client) {
+	if err := h.gameService.UnwatchGame(ctx, c.gameID, c.userID, c.id); err != nil {
+		log.Printf("Failed to remove spectator of game %s: %v", c.gameID.Hex(), err)
+	}
+}
+
+// send queues a message for a single client
+func (h *Handler) send(c *client, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("Failed to encode WebSocket message for game %s: %v", c.gameID.Hex(), err)
+		return
+	}
+	c.enqueue(data)
+}
+
+// sendError reports an error to a single client
+func (h *Handler) sendError(c *client, message string) {
+	h.send(c, errorMessage{Type: "error", Message: message})
+}
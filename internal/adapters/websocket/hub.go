@@ -0,0 +1,146 @@
+// Package websocket provides real-time game updates over WebSocket connections.
+// This is part of the Adapters layer in Hexagonal Architecture.
+package websocket
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+
+	"chess-backend/internal/domain/game"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// listener receives the events of one game. Implementations must not block.
+type listener interface {
+	deliver(event game.Event, data []byte)
+}
+
+// Hub keeps track of the sockets and other listeners of each game and fans out game
+// events to them. Events addressed to a single user go to that user's listeners.
+type Hub struct {
+	mu        sync.RWMutex
+	listeners map[primitive.ObjectID]map[listener]struct{} // By game
+	users     map[primitive.ObjectID]map[listener]struct{} // By user
+}
+
+// NewHub creates an empty hub
+func NewHub() *Hub {
+	return &Hub{
+		listeners: make(map[primitive.ObjectID]map[listener]struct{}),
+		users:     make(map[primitive.ObjectID]map[listener]struct{}),
+	}
+}
+
+// Broadcast sends an event to every listener of the event's game, or of its user
+func (h *Hub) Broadcast(event game.Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to encode %s event for game %s: %v", event.Type, event.GameID.Hex(), err)
+		return
+	}
+
+	listeners, id := h.listeners, event.GameID
+	if event.UserID != nil {
+		listeners, id = h.users, *event.UserID
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for l := range listeners[id] {
+		l.deliver(event, data)
+	}
+}
+
+// Subscribe returns a channel receiving the events of a game, for transports other
+// than WebSocket. The channel is closed when the subscriber falls behind or after
+// the returned cancel function is called.
+func (h *Hub) Subscribe(gameID primitive.ObjectID) (<-chan game.Event, func()) {
+	return h.subscribe(h.listeners, gameID)
+}
+
+// SubscribeUser returns a channel receiving the events addressed to a user, like
+// Subscribe does for the events of a game
+func (h *Hub) SubscribeUser(userID primitive.ObjectID) (<-chan game.Event, func()) {
+	return h.subscribe(h.users, userID)
+}
+
+// subscribe adds a channel listener under an ID of the given listener map
+func (h *Hub) subscribe(listeners map[primitive.ObjectID]map[listener]struct{}, id primitive.ObjectID) (<-chan game.Event, func()) {
+	s := &subscription{events: make(chan game.Event, sendBufferSize)}
+	h.add(listeners, id, s)
+
+	cancel := func() {
+		h.remove(listeners, id, s)
+		s.close()
+	}
+	return s.events, cancel
+}
+
+// register adds a listener to a game
+func (h *Hub) register(gameID primitive.ObjectID, l listener) {
+	h.add(h.listeners, gameID, l)
+}
+
+// unregister removes a listener from a game
+func (h *Hub) unregister(gameID primitive.ObjectID, l listener) {
+	h.remove(h.listeners, gameID, l)
+}
+
+// add adds a listener under an ID of a listener map
+func (h *Hub) add(listeners map[primitive.ObjectID]map[listener]struct{}, id primitive.ObjectID, l listener) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if listeners[id] == nil {
+		listeners[id] = make(map[listener]struct{})
+	}
+	listeners[id][l] = struct{}{}
+}
+
+// remove removes a listener from under an ID of a listener map
+func (h *Hub) remove(listeners map[primitive.ObjectID]map[listener]struct{}, id primitive.ObjectID, l listener) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(listeners[id], l)
+	if len(listeners[id]) == 0 {
+		delete(listeners, id)
+	}
+}
+
+// subscription is a listener that hands decoded events to a channel
+type subscription struct {
+	events chan game.Event
+
+	mu     sync.Mutex // Guards closing events against concurrent deliveries
+	closed bool
+}
+
+// deliver queues an event, closing the channel if the subscriber cannot keep up
+func (s *subscription) deliver(event game.Event, _ []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	select {
+	case s.events <- event:
+	default:
+		s.closed = true
+		close(s.events)
+	}
+}
+
+// close closes the channel unless it already is
+func (s *subscription) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.closed {
+		s.closed = true
+		close(s.events)
+	}
+}
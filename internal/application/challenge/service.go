@@ -0,0 +1,259 @@
+// Package challenge contains the Challenge application service implementation.
+// This is part of the Application layer in Hexagonal Architecture.
+// Application services orchestrate domain entities and repository operations.
+package challenge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"chess-backend/internal/domain/challenge"
+	"chess-backend/internal/domain/game"
+	"chess-backend/internal/ports/events"
+	"chess-backend/internal/ports/repositories"
+	"chess-backend/internal/ports/services"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// challengeService implements the ChallengeService interface
+type challengeService struct {
+	challengeRepo repositories.ChallengeRepository
+	userRepo      repositories.UserRepository
+	gameService   services.GameService
+	publisher     events.GameEventPublisher
+	ttl           time.Duration
+}
+
+// NewChallengeService creates a new instance of ChallengeService. Challenges
+// expire when they are not answered within ttl.
+func NewChallengeService(challengeRepo repositories.ChallengeRepository, userRepo repositories.UserRepository, gameService services.GameService, publisher events.GameEventPublisher, ttl time.Duration) services.ChallengeService {
+	return &challengeService{
+		challengeRepo: challengeRepo,
+		userRepo:      userRepo,
+		gameService:   gameService,
+		publisher:     publisher,
+		ttl:           ttl,
+	}
+}
+
+// CreateChallenge sends a challenge to another player
+func (s *challengeService) CreateChallenge(ctx context.Context, req services.CreateChallengeRequest) (*services.ChallengeResponse, error) {
+	// Validate request
+	if req.ChallengerID.IsZero() {
+		return nil, errors.New("challenger ID is required")
+	}
+	if req.Username == "" {
+		return nil, errors.New("username is required")
+	}
+
+	// Find the challenged player
+	opponent, err := s.userRepo.FindByUsername(ctx, req.Username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find player: %w", err)
+	}
+
+	// Create the challenge using domain logic
+	c, err := challenge.NewChallenge(req.ChallengerID, opponent.ID, challenge.Options{
+		TimeControl: req.TimeControl,
+		Color:       req.Color,
+		Rated:       req.Rated,
+	}, s.ttl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create challenge: %w", err)
+	}
+
+	// Save challenge to repository
+	if err := s.challengeRepo.Save(ctx, c); err != nil {
+		return nil, fmt.Errorf("failed to save challenge: %w", err)
+	}
+
+	return newChallengeResponse(fmt.Sprintf("Challenge sent to %s", opponent.Username), c), nil
+}
+
+// GetChallenge retrieves a challenge if the user sent or received it
+func (s *challengeService) GetChallenge(ctx context.Context, challengeID primitive.ObjectID, userID primitive.ObjectID) (*services.ChallengeResponse, error) {
+	c, err := s.findChallenge(ctx, challengeID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Expire(time.Now())
+	return newChallengeResponse("Challenge retrieved successfully", c), nil
+}
+
+// ListChallenges retrieves the pending challenges of a user, split into received and sent
+func (s *challengeService) ListChallenges(ctx context.Context, userID primitive.ObjectID) (*services.ChallengeListResponse, error) {
+	if userID.IsZero() {
+		return nil, errors.New("user ID is required")
+	}
+
+	challenges, err := s.challengeRepo.FindPendingByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find challenges: %w", err)
+	}
+
+	list := &services.ChallengeListResponse{
+		Incoming: []*challenge.Challenge{},
+		Outgoing: []*challenge.Challenge{},
+	}
+	now := time.Now()
+	for _, c := range challenges {
+		// Skip challenges that expired since the last sweep
+		if c.IsExpired(now) {
+			continue
+		}
+		if c.ChallengedID == userID {
+			list.Incoming = append(list.Incoming, c)
+		} else {
+			list.Outgoing = append(list.Outgoing, c)
+		}
+	}
+
+	return list, nil
+}
+
+// AcceptChallenge starts the game of a challenge with both players seated
+func (s *challengeService) AcceptChallenge(ctx context.Context, challengeID primitive.ObjectID, userID primitive.ObjectID) (*services.ChallengeResponse, error) {
+	c, err := s.findChallenge(ctx, challengeID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.CheckAccept(userID); err != nil {
+		return nil, fmt.Errorf("failed to accept challenge: %w", err)
+	}
+
+	white, black, err := c.Players()
+	if err != nil {
+		return nil, fmt.Errorf("failed to accept challenge: %w", err)
+	}
+	started, err := s.gameService.StartGame(ctx, services.StartGameRequest{
+		WhitePlayerID: white,
+		BlackPlayerID: black,
+		TimeControl:   c.TimeControl,
+		Rated:         c.Rated,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start game: %w", err)
+	}
+
+	if err := c.Accept(userID, started.Game.ID); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to accept challenge: %w", err), s.discardGame(ctx, started.Game.ID))
+	}
+	if err := s.challengeRepo.Respond(ctx, c); err != nil {
+		// The challenge was answered elsewhere in the meantime, so the game is not needed
+		return nil, errors.Join(fmt.Errorf("failed to accept challenge: %w", err), s.discardGame(ctx, started.Game.ID))
+	}
+
+	s.notify(ctx, game.NewUserEvent(game.EventChallengeAccepted, started.Game, c.ChallengerID))
+	return newChallengeResponse("Challenge accepted", c), nil
+}
+
+// notify sends an event to a player. Failures are only logged: the player still
+// finds the game on their challenge.
+func (s *challengeService) notify(ctx context.Context, event game.Event) {
+	if err := s.publisher.Publish(context.WithoutCancel(ctx), event); err != nil {
+		log.Printf("Failed to notify user %s of game %s: %v", event.UserID.Hex(), event.GameID.Hex(), err)
+	}
+}
+
+// DeclineChallenge turns down a challenge the user received
+func (s *challengeService) DeclineChallenge(ctx context.Context, challengeID primitive.ObjectID, userID primitive.ObjectID) (*services.ChallengeResponse, error) {
+	c, err := s.findChallenge(ctx, challengeID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.Decline(userID); err != nil {
+		return nil, fmt.Errorf("failed to decline challenge: %w", err)
+	}
+	if err := s.challengeRepo.Respond(ctx, c); err != nil {
+		return nil, fmt.Errorf("failed to decline challenge: %w", err)
+	}
+
+	return newChallengeResponse("Challenge declined", c), nil
+}
+
+// CancelChallenge withdraws a challenge the user sent
+func (s *challengeService) CancelChallenge(ctx context.Context, challengeID primitive.ObjectID, userID primitive.ObjectID) (*services.ChallengeResponse, error) {
+	c, err := s.findChallenge(ctx, challengeID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.Cancel(userID); err != nil {
+		return nil, fmt.Errorf("failed to cancel challenge: %w", err)
+	}
+	if err := s.challengeRepo.Respond(ctx, c); err != nil {
+		return nil, fmt.Errorf("failed to cancel challenge: %w", err)
+	}
+
+	return newChallengeResponse("Challenge cancelled", c), nil
+}
+
+// ExpireChallenges marks pending challenges past their expiry as expired
+func (s *challengeService) ExpireChallenges(ctx context.Context) (int, error) {
+	now := time.Now()
+	challenges, err := s.challengeRepo.FindExpired(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find expired challenges: %w", err)
+	}
+
+	expired := 0
+	for _, c := range challenges {
+		if !c.Expire(now) {
+			continue
+		}
+		// A challenge answered just before its expiry keeps the answer
+		if err := s.challengeRepo.Respond(ctx, c); err != nil {
+			log.Printf("Failed to expire challenge %s: %v", c.ID.Hex(), err)
+			continue
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+// findChallenge retrieves a challenge and checks that the user sent or received it
+func (s *challengeService) findChallenge(ctx context.Context, challengeID primitive.ObjectID, userID primitive.ObjectID) (*challenge.Challenge, error) {
+	if challengeID.IsZero() {
+		return nil, errors.New("challenge ID is required")
+	}
+	if userID.IsZero() {
+		return nil, errors.New("user ID is required")
+	}
+
+	c, err := s.challengeRepo.FindByID(ctx, challengeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find challenge: %w", err)
+	}
+	if !c.Involves(userID) {
+		return nil, errors.New("user is not part of this challenge")
+	}
+
+	return c, nil
+}
+
+// discardGame removes a game that was started for a challenge that could not be accepted
+func (s *challengeService) discardGame(ctx context.Context, gameID primitive.ObjectID) error {
+	if err := s.gameService.DeleteGame(context.WithoutCancel(ctx), gameID); err != nil {
+		return fmt.Errorf("failed to discard game: %w", err)
+	}
+	return nil
+}
+
+// newChallengeResponse builds the response for an operation on a challenge
+func newChallengeResponse(message string, c *challenge.Challenge) *services.ChallengeResponse {
+	resp := &services.ChallengeResponse{
+		Message:   message,
+		Challenge: c,
+	}
+	if c.Status == challenge.ChallengeStatusAccepted {
+		resp.GameID = c.GameID.Hex()
+	}
+	return resp
+}
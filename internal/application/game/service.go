@@ -7,8 +7,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"time"
 
 	"chess-backend/internal/domain/game"
+	"chess-backend/internal/domain/rating"
+	"chess-backend/internal/ports/events"
 	"chess-backend/internal/ports/repositories"
 	"chess-backend/internal/ports/services"
 
@@ -17,13 +21,23 @@ import (
 
 // gameService implements the GameService interface
 type gameService struct {
-	gameRepo repositories.GameRepository
+	gameRepo      repositories.GameRepository
+	userRepo      repositories.UserRepository
+	ratingRepo    repositories.RatingHistoryRepository
+	spectatorRepo repositories.SpectatorRepository
+	presenceRepo  repositories.PresenceRepository
+	publisher     events.GameEventPublisher
 }
 
 // NewGameService creates a new instance of GameService
-func NewGameService(gameRepo repositories.GameRepository) services.GameService {
+func NewGameService(gameRepo repositories.GameRepository, userRepo repositories.UserRepository, ratingRepo repositories.RatingHistoryRepository, spectatorRepo repositories.SpectatorRepository, presenceRepo repositories.PresenceRepository, publisher events.GameEventPublisher) services.GameService {
 	return &gameService{
-		gameRepo: gameRepo,
+		gameRepo:      gameRepo,
+		userRepo:      userRepo,
+		ratingRepo:    ratingRepo,
+		spectatorRepo: spectatorRepo,
+		presenceRepo:  presenceRepo,
+		publisher:     publisher,
 	}
 }
 
@@ -35,7 +49,14 @@ func (s *gameService) CreateGame(ctx context.Context, req services.CreateGameReq
 	}
 
 	// Create new game using domain entity
-	newGame, err := game.NewGame(req.PlayerID)
+	newGame, err := game.NewGame(req.PlayerID, game.GameOptions{
+		TimeControl:     req.TimeControl,
+		AllowSpectators: req.AllowSpectators,
+		Rated:           req.Rated,
+		Color:           req.Color,
+		Private:         req.Private,
+		AllowTakebacks:  req.AllowTakebacks,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create game: %w", err)
 	}
@@ -45,11 +66,9 @@ func (s *gameService) CreateGame(ctx context.Context, req services.CreateGameReq
 		return nil, fmt.Errorf("failed to save game: %w", err)
 	}
 
-	return &services.GameResponse{
-		Message: "Game created successfully",
-		Game:    newGame,
-		GameID:  newGame.ID.Hex(),
-	}, nil
+	resp := s.newGameResponse(ctx, "Game created successfully", newGame)
+	resp.InviteCode = newGame.InviteCode
+	return resp, nil
 }
 
 // JoinGame allows a player to join an existing game
@@ -69,23 +88,45 @@ func (s *gameService) JoinGame(ctx context.Context, req services.JoinGameRequest
 	}
 
 	// Join the game using domain logic
-	if err := gameEntity.JoinGame(req.PlayerID); err != nil {
+	if err := gameEntity.JoinGame(req.PlayerID, req.InviteCode); err != nil {
 		return nil, fmt.Errorf("failed to join game: %w", err)
 	}
 
 	// Update game in repository
-	if err := s.gameRepo.Update(ctx, gameEntity); err != nil {
+	if err := s.updateGame(ctx, gameEntity); err != nil {
 		return nil, fmt.Errorf("failed to update game: %w", err)
 	}
+	s.publish(ctx, game.NewEvent(game.EventGameJoined, gameEntity))
 
-	return &services.GameResponse{
-		Message: "Successfully joined game",
-		Game:    gameEntity,
-		GameID:  gameEntity.ID.Hex(),
-	}, nil
+	return s.newGameResponse(ctx, "Successfully joined game", gameEntity), nil
 }
 
-// GetGame retrieves a game by ID if the player is authorized
+// StartGame creates a game between two players that begins right away
+func (s *gameService) StartGame(ctx context.Context, req services.StartGameRequest) (*services.GameResponse, error) {
+	// Validate request
+	if req.WhitePlayerID.IsZero() || req.BlackPlayerID.IsZero() {
+		return nil, errors.New("white and black player IDs are required")
+	}
+
+	// Create the game with both players using domain logic
+	newGame, err := game.NewGameBetween(req.WhitePlayerID, req.BlackPlayerID, game.GameOptions{
+		TimeControl:     req.TimeControl,
+		AllowSpectators: req.AllowSpectators,
+		Rated:           req.Rated,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create game: %w", err)
+	}
+
+	// Save game to repository
+	if err := s.gameRepo.Save(ctx, newGame); err != nil {
+		return nil, fmt.Errorf("failed to save game: %w", err)
+	}
+
+	return s.newGameResponse(ctx, "Game started successfully", newGame), nil
+}
+
+// GetGame retrieves a game by ID if the user is a player or the game allows spectators
 func (s *gameService) GetGame(ctx context.Context, gameID primitive.ObjectID, playerID primitive.ObjectID) (*game.Game, error) {
 	// Validate input
 	if gameID.IsZero() {
@@ -101,10 +142,12 @@ func (s *gameService) GetGame(ctx context.Context, gameID primitive.ObjectID, pl
 		return nil, fmt.Errorf("failed to find game: %w", err)
 	}
 
-	// Check if player is authorized to view this game
-	if !gameEntity.IsPlayerInGame(playerID) {
+	// Check if the user is authorized to view this game
+	if !gameEntity.CanView(playerID) {
 		return nil, errors.New("player is not authorized to view this game")
 	}
+	s.countSpectators(ctx, gameEntity)
+	s.markPresent(ctx, gameEntity, playerID)
 
 	return gameEntity, nil
 }
@@ -118,8 +161,8 @@ func (s *gameService) MakeMove(ctx context.Context, req services.MakeMoveRequest
 	if req.PlayerID.IsZero() {
 		return nil, errors.New("player ID is required")
 	}
-	if req.From == "" || req.To == "" {
-		return nil, errors.New("from and to positions are required")
+	if req.Move == "" && (req.From == "" || req.To == "") {
+		return nil, errors.New("a move or from and to positions are required")
 	}
 
 	// Find the game
@@ -127,22 +170,37 @@ func (s *gameService) MakeMove(ctx context.Context, req services.MakeMoveRequest
 	if err != nil {
 		return nil, fmt.Errorf("failed to find game: %w", err)
 	}
+	s.markPresent(ctx, gameEntity, req.PlayerID)
 
 	// Make the move using domain logic
-	if err := gameEntity.MakeMove(req.PlayerID, req.From, req.To, req.Piece, req.Notation); err != nil {
+	if req.Move != "" {
+		err = gameEntity.MakeMoveNotation(req.PlayerID, req.Move)
+	} else {
+		err = gameEntity.MakeMove(req.PlayerID, req.From, req.To, req.Promotion)
+	}
+	if errors.Is(err, game.ErrTimeExpired) {
+		// The game was lost on time, persist the result before rejecting the move
+		if updateErr := s.updateGame(ctx, gameEntity); updateErr != nil {
+			return nil, fmt.Errorf("failed to update game: %w", updateErr)
+		}
+		s.publish(ctx, game.NewEvent(game.EventGameFinished, gameEntity))
+	}
+	if err != nil {
 		return nil, fmt.Errorf("failed to make move: %w", err)
 	}
 
 	// Update game in repository
-	if err := s.gameRepo.Update(ctx, gameEntity); err != nil {
+	if err := s.updateGame(ctx, gameEntity); err != nil {
 		return nil, fmt.Errorf("failed to update game: %w", err)
 	}
+	s.publish(ctx, game.MoveEvents(gameEntity)...)
 
-	return &services.GameResponse{
-		Message: "Move made successfully",
-		Game:    gameEntity,
-		GameID:  gameEntity.ID.Hex(),
-	}, nil
+	message := "Move made successfully"
+	if gameEntity.Status == game.GameStatusFinished {
+		message = fmt.Sprintf("Move made successfully, game over by %s", gameEntity.Termination)
+	}
+
+	return s.newGameResponse(ctx, message, gameEntity), nil
 }
 
 // ResignGame allows a player to resign from a game
@@ -160,6 +218,7 @@ func (s *gameService) ResignGame(ctx context.Context, req services.ResignGameReq
 	if err != nil {
 		return nil, fmt.Errorf("failed to find game: %w", err)
 	}
+	s.markPresent(ctx, gameEntity, req.PlayerID)
 
 	// Resign from the game using domain logic
 	if err := gameEntity.ResignGame(req.PlayerID); err != nil {
@@ -167,17 +226,203 @@ func (s *gameService) ResignGame(ctx context.Context, req services.ResignGameReq
 	}
 
 	// Update game in repository
-	if err := s.gameRepo.Update(ctx, gameEntity); err != nil {
+	if err := s.updateGame(ctx, gameEntity); err != nil {
 		return nil, fmt.Errorf("failed to update game: %w", err)
 	}
+	s.publish(ctx, game.NewEvent(game.EventGameResigned, gameEntity))
+
+	return s.newGameResponse(ctx, "Successfully resigned from game", gameEntity), nil
+}
+
+// AbortGame calls a game off before both players have moved
+func (s *gameService) AbortGame(ctx context.Context, req services.AbortGameRequest) (*services.GameResponse, error) {
+	return s.playerAction(ctx, req.GameID, req.PlayerID, (*game.Game).AbortGame, "abort game", game.EventGameAborted, "Game aborted")
+}
+
+// ClaimDraw ends a game as a draw when the move history supports the claim
+func (s *gameService) ClaimDraw(ctx context.Context, req services.ClaimDrawRequest) (*services.GameResponse, error) {
+	// Validate request
+	if req.GameID.IsZero() {
+		return nil, errors.New("game ID is required")
+	}
+	if req.PlayerID.IsZero() {
+		return nil, errors.New("player ID is required")
+	}
+
+	// Find the game
+	gameEntity, err := s.gameRepo.FindByID(ctx, req.GameID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find game: %w", err)
+	}
+	s.markPresent(ctx, gameEntity, req.PlayerID)
+
+	// Claim the draw using domain logic
+	if err := gameEntity.ClaimDraw(req.PlayerID); err != nil {
+		return nil, fmt.Errorf("failed to claim draw: %w", err)
+	}
+
+	// Update game in repository
+	if err := s.updateGame(ctx, gameEntity); err != nil {
+		return nil, fmt.Errorf("failed to update game: %w", err)
+	}
+	s.publish(ctx, game.NewEvent(game.EventGameFinished, gameEntity))
+
+	return s.newGameResponse(ctx, fmt.Sprintf("Draw claimed by %s", gameEntity.Termination), gameEntity), nil
+}
+
+// CancelGame removes a waiting game on behalf of its creator
+func (s *gameService) CancelGame(ctx context.Context, req services.CancelGameRequest) (*services.GameResponse, error) {
+	// Validate request
+	if req.GameID.IsZero() {
+		return nil, errors.New("game ID is required")
+	}
+	if req.PlayerID.IsZero() {
+		return nil, errors.New("player ID is required")
+	}
+
+	// Find the game
+	gameEntity, err := s.gameRepo.FindByID(ctx, req.GameID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find game: %w", err)
+	}
+
+	// Check the cancellation using domain logic
+	if err := gameEntity.CheckCancel(req.PlayerID); err != nil {
+		return nil, fmt.Errorf("failed to cancel game: %w", err)
+	}
+
+	// Remove game from repository, unless an opponent joined in the meantime
+	if err := s.gameRepo.DeleteWaiting(ctx, gameEntity); err != nil {
+		return nil, fmt.Errorf("failed to delete game: %w", err)
+	}
+	s.publish(ctx, game.NewEvent(game.EventGameCancelled, gameEntity))
 
 	return &services.GameResponse{
-		Message: "Successfully resigned from game",
+		Message: "Game cancelled",
 		Game:    gameEntity,
 		GameID:  gameEntity.ID.Hex(),
 	}, nil
 }
 
+// ClaimAbandonment ends a game whose opponent is gone, as a win or a draw
+func (s *gameService) ClaimAbandonment(ctx context.Context, req services.ClaimAbandonmentRequest) (*services.GameResponse, error) {
+	// Validate request
+	if req.GameID.IsZero() {
+		return nil, errors.New("game ID is required")
+	}
+	if req.PlayerID.IsZero() {
+		return nil, errors.New("player ID is required")
+	}
+
+	// Find the game
+	gameEntity, err := s.gameRepo.FindByID(ctx, req.GameID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find game: %w", err)
+	}
+	s.markPresent(ctx, gameEntity, req.PlayerID)
+
+	// Find out when the opponent was last connected
+	opponentID, err := gameEntity.OpponentOf(req.PlayerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim abandonment: %w", err)
+	}
+	lastSeen, err := s.presenceRepo.LastSeen(ctx, req.GameID, opponentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check opponent presence: %w", err)
+	}
+
+	// Claim the game using domain logic
+	if err := gameEntity.ClaimAbandonment(req.PlayerID, req.Claim, lastSeen, services.AbandonmentGracePeriod, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to claim abandonment: %w", err)
+	}
+
+	// Update game in repository
+	if err := s.updateGame(ctx, gameEntity); err != nil {
+		return nil, fmt.Errorf("failed to update game: %w", err)
+	}
+	s.publish(ctx, game.NewEvent(game.EventGameFinished, gameEntity))
+
+	return s.newGameResponse(ctx, "Game claimed after the opponent abandoned it", gameEntity), nil
+}
+
+// RecordPresence records that a player is connected to a game
+func (s *gameService) RecordPresence(ctx context.Context, gameID primitive.ObjectID, playerID primitive.ObjectID) error {
+	if gameID.IsZero() {
+		return errors.New("game ID is required")
+	}
+	if playerID.IsZero() {
+		return errors.New("player ID is required")
+	}
+
+	if err := s.presenceRepo.Touch(ctx, gameID, playerID, time.Now()); err != nil {
+		return fmt.Errorf("failed to record presence: %w", err)
+	}
+
+	return nil
+}
+
+// OfferDraw offers the opponent a draw
+func (s *gameService) OfferDraw(ctx context.Context, req services.DrawOfferRequest) (*services.GameResponse, error) {
+	return s.playerAction(ctx, req.GameID, req.PlayerID, (*game.Game).OfferDraw, "offer draw", game.EventDrawOffered, "Draw offered")
+}
+
+// AcceptDraw ends the game as a draw by agreement
+func (s *gameService) AcceptDraw(ctx context.Context, req services.DrawOfferRequest) (*services.GameResponse, error) {
+	return s.playerAction(ctx, req.GameID, req.PlayerID, (*game.Game).AcceptDraw, "accept draw", game.EventGameFinished, "Draw agreed")
+}
+
+// DeclineDraw declines the opponent's draw offer
+func (s *gameService) DeclineDraw(ctx context.Context, req services.DrawOfferRequest) (*services.GameResponse, error) {
+	return s.playerAction(ctx, req.GameID, req.PlayerID, (*game.Game).DeclineDraw, "decline draw", game.EventDrawDeclined, "Draw declined")
+}
+
+// RequestTakeback asks the opponent to let the player take back their last move
+func (s *gameService) RequestTakeback(ctx context.Context, req services.TakebackRequest) (*services.GameResponse, error) {
+	return s.playerAction(ctx, req.GameID, req.PlayerID, (*game.Game).RequestTakeback, "request takeback", game.EventTakebackRequested, "Takeback requested")
+}
+
+// AcceptTakeback takes back the opponent's last move
+func (s *gameService) AcceptTakeback(ctx context.Context, req services.TakebackRequest) (*services.GameResponse, error) {
+	return s.playerAction(ctx, req.GameID, req.PlayerID, (*game.Game).AcceptTakeback, "accept takeback", game.EventTakeback, "Takeback accepted")
+}
+
+// DeclineTakeback declines the opponent's takeback request
+func (s *gameService) DeclineTakeback(ctx context.Context, req services.TakebackRequest) (*services.GameResponse, error) {
+	return s.playerAction(ctx, req.GameID, req.PlayerID, (*game.Game).DeclineTakeback, "decline takeback", game.EventTakebackDeclined, "Takeback declined")
+}
+
+// playerAction applies an operation of the domain that a player performs on a game,
+// saves the game and notifies the players
+func (s *gameService) playerAction(ctx context.Context, gameID, playerID primitive.ObjectID, apply func(*game.Game, primitive.ObjectID) error, action string, eventType game.EventType, message string) (*services.GameResponse, error) {
+	// Validate request
+	if gameID.IsZero() {
+		return nil, errors.New("game ID is required")
+	}
+	if playerID.IsZero() {
+		return nil, errors.New("player ID is required")
+	}
+
+	// Find the game
+	gameEntity, err := s.gameRepo.FindByID(ctx, gameID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find game: %w", err)
+	}
+	s.markPresent(ctx, gameEntity, playerID)
+
+	// Apply the operation using domain logic
+	if err := apply(gameEntity, playerID); err != nil {
+		return nil, fmt.Errorf("failed to %s: %w", action, err)
+	}
+
+	// Update game in repository
+	if err := s.updateGame(ctx, gameEntity); err != nil {
+		return nil, fmt.Errorf("failed to update game: %w", err)
+	}
+	s.publish(ctx, game.NewEvent(eventType, gameEntity))
+
+	return s.newGameResponse(ctx, message, gameEntity), nil
+}
+
 // ListPlayerGames retrieves all games for a specific player with pagination
 func (s *gameService) ListPlayerGames(ctx context.Context, playerID primitive.ObjectID, page, limit int) (*services.GameListResponse, error) {
 	if playerID.IsZero() {
@@ -210,17 +455,15 @@ func (s *gameService) ListPlayerGames(ctx context.Context, playerID primitive.Ob
 	}, nil
 }
 
-// ListWaitingGames retrieves all games waiting for players with pagination
+// ListWaitingGames retrieves all public games waiting for players with pagination
 func (s *gameService) ListWaitingGames(ctx context.Context, page, limit int) (*services.GameListResponse, error) {
 	games, err := s.gameRepo.FindWaitingGames(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find waiting games: %w", err)
 	}
 
-	total, err := s.gameRepo.CountByStatus(ctx, game.GameStatusWaiting)
-	if err != nil {
-		return nil, fmt.Errorf("failed to count waiting games: %w", err)
-	}
+	// Private games are not listed, so they are not counted either
+	total := int64(len(games))
 
 	// Convert to response format
 	gameList := make([]game.Game, len(games))
@@ -277,12 +520,42 @@ func (s *gameService) GetGameHistory(ctx context.Context, gameID primitive.Objec
 		return nil, fmt.Errorf("failed to find game: %w", err)
 	}
 
+	// Check if the user is authorized to view this game
+	if !gameEntity.CanView(playerID) {
+		return nil, errors.New("player is not authorized to view this game")
+	}
+	s.markPresent(ctx, gameEntity, playerID)
+
+	return gameEntity.Moves, nil
+}
+
+// GetLegalMoves retrieves the legal moves for the side to move in a game
+func (s *gameService) GetLegalMoves(ctx context.Context, gameID primitive.ObjectID, playerID primitive.ObjectID, from string) ([]game.LegalMove, error) {
+	if gameID.IsZero() {
+		return nil, errors.New("game ID is required")
+	}
+	if playerID.IsZero() {
+		return nil, errors.New("player ID is required")
+	}
+
+	// Find the game
+	gameEntity, err := s.gameRepo.FindByID(ctx, gameID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find game: %w", err)
+	}
+
 	// Check if player is authorized to view this game
 	if !gameEntity.IsPlayerInGame(playerID) {
 		return nil, errors.New("player is not authorized to view this game")
 	}
+	s.markPresent(ctx, gameEntity, playerID)
 
-	return gameEntity.Moves, nil
+	moves, err := gameEntity.LegalMoves(from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list legal moves: %w", err)
+	}
+
+	return moves, nil
 }
 
 // IsPlayerInGame checks if a player is participating in a specific game
@@ -311,22 +584,45 @@ func (s *gameService) GetPlayerStats(ctx context.Context, playerID primitive.Obj
 		return nil, fmt.Errorf("failed to find player games: %w", err)
 	}
 
+	// Aborted games and waiting games that expired without an opponent are not counted
+	played := 0
+	for _, g := range games {
+		if g.Status != game.GameStatusAborted && g.Result != game.GameResultAbandoned {
+			played++
+		}
+	}
+
 	// Calculate statistics
 	stats := map[string]interface{}{
-		"total_games": len(games),
+		"total_games": played,
 		"wins":        0,
 		"losses":      0,
 		"draws":       0,
 		"active_games": 0,
 	}
 
+	// Ratings per time control category
+	player, err := s.userRepo.FindByID(ctx, playerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find player: %w", err)
+	}
+	ratings := make(map[rating.Category]interface{}, len(player.Ratings))
+	for category, r := range player.Ratings {
+		ratings[category] = map[string]interface{}{
+			"rating":      r.Rounded(),
+			"games":       r.Games,
+			"provisional": r.Provisional(),
+		}
+	}
+	stats["ratings"] = ratings
+
 	for _, g := range games {
 		if g.Status == game.GameStatusActive {
 			stats["active_games"] = stats["active_games"].(int) + 1
 			continue
 		}
 
-		if g.Status == game.GameStatusFinished {
+		if g.IsDecided() {
 			switch g.Result {
 			case game.GameResultWhiteWins:
 				if g.WhitePlayer == playerID {
@@ -349,6 +645,157 @@ func (s *gameService) GetPlayerStats(ctx context.Context, playerID primitive.Obj
 	return stats, nil
 }
 
+// ExpireTimedOutGames finishes active timed games whose side to move has run out of time.
+// Correspondence games are left to ExpireCorrespondenceGames.
+func (s *gameService) ExpireTimedOutGames(ctx context.Context) (int, error) {
+	return s.expireGames(ctx, false)
+}
+
+// ExpireCorrespondenceGames finishes correspondence games whose move deadline has passed
+func (s *gameService) ExpireCorrespondenceGames(ctx context.Context) (int, error) {
+	return s.expireGames(ctx, true)
+}
+
+// expireGames flags either the correspondence games or the other timed games
+func (s *gameService) expireGames(ctx context.Context, correspondence bool) (int, error) {
+	now := time.Now()
+	games, err := s.gameRepo.FindDueGames(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find games out of time: %w", err)
+	}
+
+	expired := 0
+	for _, g := range games {
+		if g.IsCorrespondence() != correspondence {
+			continue
+		}
+		flagged, err := s.sweepGame(ctx, g, func(g *game.Game) bool {
+			return g.CheckFlag(now)
+		}, game.EventGameFinished)
+		if err != nil {
+			return expired, err
+		}
+		if flagged {
+			expired++
+		}
+	}
+
+	return expired, nil
+}
+
+// AbortUnplayedGames aborts active games whose first moves were not made in time
+func (s *gameService) AbortUnplayedGames(ctx context.Context, window time.Duration) (int, error) {
+	games, err := s.gameRepo.FindUnplayedGames(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find unplayed games: %w", err)
+	}
+
+	aborted := 0
+	now := time.Now()
+	for _, g := range games {
+		// A move made after the game was loaded is checked again instead of overwritten
+		abortedGame, err := s.sweepGame(ctx, g, func(g *game.Game) bool {
+			return g.AbortIfUnplayed(now, window)
+		}, game.EventGameAborted)
+		if err != nil {
+			return aborted, err
+		}
+		if abortedGame {
+			aborted++
+		}
+	}
+
+	return aborted, nil
+}
+
+// ExpireWaitingGames abandons games, public or private, that nobody joined in time
+func (s *gameService) ExpireWaitingGames(ctx context.Context, ttl time.Duration) (int, error) {
+	games, err := s.gameRepo.FindByStatus(ctx, game.GameStatusWaiting)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find waiting games: %w", err)
+	}
+
+	expired := 0
+	now := time.Now()
+	for _, g := range games {
+		// A player who joined after the game was loaded keeps it
+		expiredGame, err := s.sweepGame(ctx, g, func(g *game.Game) bool {
+			return g.ExpireIfStale(now, ttl)
+		}, game.EventGameCancelled)
+		if err != nil {
+			return expired, err
+		}
+		if expiredGame {
+			expired++
+		}
+	}
+
+	return expired, nil
+}
+
+// sweepGame applies a change made by a background job to a game and saves it. When a
+// player changed the game after it was loaded, the current game is loaded and checked
+// again, so the player's change is never overwritten; if it changes yet again, the
+// game is left to the next run. It reports whether the game was changed.
+func (s *gameService) sweepGame(ctx context.Context, g *game.Game, change func(*game.Game) bool, eventType game.EventType) (bool, error) {
+	if !change(g) {
+		return false, nil
+	}
+	err := s.updateGame(ctx, g)
+	if errors.Is(err, repositories.ErrGameModified) {
+		if g, err = s.gameRepo.FindByID(ctx, g.ID); err != nil {
+			return false, fmt.Errorf("failed to find game: %w", err)
+		}
+		if !change(g) {
+			return false, nil
+		}
+		err = s.updateGame(ctx, g)
+	}
+	if errors.Is(err, repositories.ErrGameModified) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to update game: %w", err)
+	}
+
+	s.publish(ctx, game.NewEvent(eventType, g))
+	return true, nil
+}
+
+// WatchGame counts a connection of a user as watching a game until the next heartbeat is due
+func (s *gameService) WatchGame(ctx context.Context, gameID primitive.ObjectID, userID primitive.ObjectID, connectionID string) error {
+	if gameID.IsZero() {
+		return errors.New("game ID is required")
+	}
+	if userID.IsZero() {
+		return errors.New("user ID is required")
+	}
+
+	// Allow one missed heartbeat before the spectator stops being counted
+	until := time.Now().Add(2 * services.SpectatorHeartbeat)
+	if err := s.spectatorRepo.Touch(ctx, gameID, userID, connectionID, until); err != nil {
+		return fmt.Errorf("failed to watch game: %w", err)
+	}
+
+	return nil
+}
+
+// UnwatchGame stops counting a connection of a user as watching a game
+func (s *gameService) UnwatchGame(ctx context.Context, gameID primitive.ObjectID, userID primitive.ObjectID, connectionID string) error {
+	if gameID.IsZero() {
+		return errors.New("game ID is required")
+	}
+	if userID.IsZero() {
+		return errors.New("user ID is required")
+	}
+
+	if err := s.spectatorRepo.Remove(ctx, gameID, userID, connectionID); err != nil {
+		return fmt.Errorf("failed to unwatch game: %w", err)
+	}
+
+	return nil
+}
+
 // DeleteGame removes a game from the repository
 func (s *gameService) DeleteGame(ctx context.Context, gameID primitive.ObjectID) error {
 	if gameID.IsZero() {
@@ -356,4 +803,204 @@ func (s *gameService) DeleteGame(ctx context.Context, gameID primitive.ObjectID)
 	}
 
 	return s.gameRepo.Delete(ctx, gameID)
+}
+
+// newGameResponse builds the response for an operation on a single game
+func (s *gameService) newGameResponse(ctx context.Context, message string, g *game.Game) *services.GameResponse {
+	s.countSpectators(ctx, g)
+	return &services.GameResponse{
+		Message:  message,
+		Game:     g,
+		GameID:   g.ID.Hex(),
+		Deadline: g.MoveDeadline(),
+	}
+}
+
+// updateGame saves a game. A rated game that has just been decided is rated once it
+// is saved; the rating worker retries games whose rating failed, so a failure is
+// only logged.
+func (s *gameService) updateGame(ctx context.Context, g *game.Game) error {
+	if err := s.gameRepo.Update(ctx, g); err != nil {
+		return err
+	}
+
+	if g.NeedsRating() {
+		// The game is decided, so rate it even if the request is cancelled
+		if err := s.rateGame(context.WithoutCancel(ctx), g); err != nil {
+			log.Printf("Failed to rate game %s, it will be retried: %v", g.ID.Hex(), err)
+		}
+	}
+	return nil
+}
+
+const (
+	// maxRatingAttempts bounds how often a player's new rating is computed again when
+	// their other games keep changing the rating
+	maxRatingAttempts = 3
+
+	// ratingRetryDelay is how long after a game ended the rating worker retries it
+	ratingRetryDelay = time.Minute
+)
+
+// rateGame applies a decided game to both players' ratings, each against the other's
+// rating before the game, and saves the rating changes on the game once both new
+// ratings are stored. A player whose rating an earlier attempt already changed is not
+// rated again.
+func (s *gameService) rateGame(ctx context.Context, g *game.Game) error {
+	whiteBefore, err := s.ratingBefore(ctx, g, g.WhitePlayer)
+	if err != nil {
+		return fmt.Errorf("failed to find white player's rating: %w", err)
+	}
+	blackBefore, err := s.ratingBefore(ctx, g, g.BlackPlayer)
+	if err != nil {
+		return fmt.Errorf("failed to find black player's rating: %w", err)
+	}
+
+	whiteBefore, whiteAfter, err := s.ratePlayer(ctx, g, g.WhitePlayer, blackBefore)
+	if err != nil {
+		return fmt.Errorf("failed to rate white player: %w", err)
+	}
+	blackBefore, blackAfter, err := s.ratePlayer(ctx, g, g.BlackPlayer, whiteBefore)
+	if err != nil {
+		return fmt.Errorf("failed to rate black player: %w", err)
+	}
+
+	g.SetRatingChanges(whiteBefore, whiteAfter, blackBefore, blackAfter)
+	if err := s.gameRepo.Update(ctx, g); err != nil {
+		g.RatingChanges = nil
+		return fmt.Errorf("failed to save rating changes: %w", err)
+	}
+	return nil
+}
+
+// ratingBefore returns a player's rating before a game
+func (s *gameService) ratingBefore(ctx context.Context, g *game.Game, playerID primitive.ObjectID) (rating.Rating, error) {
+	player, err := s.userRepo.FindByID(ctx, playerID)
+	if err != nil {
+		return rating.Rating{}, err
+	}
+	if !player.HasRatedGame(g.ID) {
+		return player.Rating(g.RatingCategory()), nil
+	}
+
+	entry, err := s.ratingRepo.FindByGame(ctx, playerID, g.ID)
+	if err != nil {
+		return rating.Rating{}, err
+	}
+	return entry.Before, nil
+}
+
+// ratePlayer applies a game to a player's rating and returns their rating before and
+// after it. The history entry is saved first, so a rating that is stored always has
+// one; when the rating changes in the meantime, both are computed again.
+func (s *gameService) ratePlayer(ctx context.Context, g *game.Game, playerID primitive.ObjectID, opponent rating.Rating) (rating.Rating, rating.Rating, error) {
+	category := g.RatingCategory()
+	for attempt := 0; attempt < maxRatingAttempts; attempt++ {
+		player, err := s.userRepo.FindByID(ctx, playerID)
+		if err != nil {
+			return rating.Rating{}, rating.Rating{}, err
+		}
+		if player.HasRatedGame(g.ID) {
+			entry, err := s.ratingRepo.FindByGame(ctx, playerID, g.ID)
+			if err != nil {
+				return rating.Rating{}, rating.Rating{}, err
+			}
+			return entry.Before, entry.After, nil
+		}
+
+		before := player.Rating(category)
+		after, err := g.RatingAfter(playerID, before, opponent)
+		if err != nil {
+			return rating.Rating{}, rating.Rating{}, err
+		}
+
+		entry := rating.NewHistoryEntry(playerID, g.ID, category, before, after)
+		if err := s.ratingRepo.Save(ctx, entry); err != nil {
+			return rating.Rating{}, rating.Rating{}, fmt.Errorf("failed to save rating history: %w", err)
+		}
+
+		err = s.userRepo.UpdateRating(ctx, playerID, category, before, after, g.ID)
+		if errors.Is(err, repositories.ErrRatingModified) {
+			continue
+		}
+		if err != nil {
+			return rating.Rating{}, rating.Rating{}, err
+		}
+		return before, after, nil
+	}
+
+	return rating.Rating{}, rating.Rating{}, repositories.ErrRatingModified
+}
+
+// RateGames rates the decided rated games whose rating failed when they ended
+func (s *gameService) RateGames(ctx context.Context) (int, error) {
+	// Games that have just ended are still being rated by the request that ended them
+	games, err := s.gameRepo.FindUnratedGames(ctx, time.Now().Add(-ratingRetryDelay))
+	if err != nil {
+		return 0, fmt.Errorf("failed to find unrated games: %w", err)
+	}
+
+	rated := 0
+	for _, g := range games {
+		// A game that cannot be rated must not hold up the others
+		if err := s.rateGame(ctx, g); err != nil {
+			log.Printf("Failed to rate game %s: %v", g.ID.Hex(), err)
+			continue
+		}
+		rated++
+	}
+
+	return rated, nil
+}
+
+// GetRatingHistory retrieves a player's latest rating changes in a category
+func (s *gameService) GetRatingHistory(ctx context.Context, playerID primitive.ObjectID, category rating.Category, limit int) ([]*rating.HistoryEntry, error) {
+	if playerID.IsZero() {
+		return nil, errors.New("player ID is required")
+	}
+	if !category.IsValid() {
+		return nil, errors.New("invalid rating category")
+	}
+
+	entries, err := s.ratingRepo.FindByUserID(ctx, playerID, category, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find rating history: %w", err)
+	}
+
+	return entries, nil
+}
+
+// publish notifies connected clients about a saved change to a game. Failures are only
+// logged: the game is already saved and clients catch up when they fetch it.
+func (s *gameService) publish(ctx context.Context, events ...game.Event) {
+	// The change is saved, so deliver the events even if the request is cancelled
+	ctx = context.WithoutCancel(ctx)
+	for _, event := range events {
+		if err := s.publisher.Publish(ctx, event); err != nil {
+			log.Printf("Failed to publish %s event for game %s: %v", event.Type, event.GameID.Hex(), err)
+		}
+	}
+}
+
+// markPresent records that a player of an active game is around, as shown by a
+// request they made. Presence only matters for abandonment claims, so a failure is
+// only logged.
+func (s *gameService) markPresent(ctx context.Context, g *game.Game, playerID primitive.ObjectID) {
+	if g.Status != game.GameStatusActive || !g.IsPlayerInGame(playerID) {
+		return
+	}
+	if err := s.presenceRepo.Touch(ctx, g.ID, playerID, time.Now()); err != nil {
+		log.Printf("Failed to record presence of player %s in game %s: %v", playerID.Hex(), g.ID.Hex(), err)
+	}
+}
+
+// countSpectators fills in the number of users watching the game. The count is
+// informational, so a failure only leaves it at zero.
+func (s *gameService) countSpectators(ctx context.Context, g *game.Game) {
+	count, err := s.spectatorRepo.Count(ctx, g.ID)
+	if err != nil {
+		log.Printf("Failed to count spectators of game %s: %v", g.ID.Hex(), err)
+		return
+	}
+	g.SpectatorCount = int(count)
 }
\ No newline at end of file
@@ -0,0 +1,79 @@
+package game
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Task is a periodic maintenance job. It returns how many games it changed.
+type Task func(ctx context.Context) (int, error)
+
+// Worker runs a Task on a fixed interval in the background until it is stopped
+type Worker struct {
+	name     string
+	interval time.Duration
+	task     Task
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewWorker creates a new background worker
+func NewWorker(name string, interval time.Duration, task Task) *Worker {
+	return &Worker{
+		name:     name,
+		interval: interval,
+		task:     task,
+	}
+}
+
+// Start launches the worker in its own goroutine
+func (w *Worker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.done = make(chan struct{})
+
+	go w.run(ctx)
+	log.Printf("Started %s (every %s)", w.name, w.interval)
+}
+
+// Stop signals the worker to stop and waits for the current run to finish
+// or for the context to expire, whichever comes first
+func (w *Worker) Stop(ctx context.Context) error {
+	if w.cancel == nil {
+		return nil
+	}
+	w.cancel()
+
+	select {
+	case <-w.done:
+		log.Printf("Stopped %s", w.name)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run executes the task on every tick until the context is cancelled
+func (w *Worker) run(ctx context.Context) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := w.task(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("%s failed: %v", w.name, err)
+				continue
+			}
+			if changed > 0 {
+				log.Printf("%s updated %d game(s)", w.name, changed)
+			}
+		}
+	}
+}
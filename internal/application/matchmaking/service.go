@@ -0,0 +1,248 @@
+// Package matchmaking contains the Matchmaking application service implementation.
+// This is part of the Application layer in Hexagonal Architecture.
+// Application services orchestrate domain entities and repository operations.
+package matchmaking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"time"
+
+	"chess-backend/internal/domain/game"
+	"chess-backend/internal/domain/matchmaking"
+	"chess-backend/internal/ports/events"
+	"chess-backend/internal/ports/repositories"
+	"chess-backend/internal/ports/services"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// matchmakingService implements the MatchmakingService interface
+type matchmakingService struct {
+	seekRepo    repositories.SeekRepository
+	userRepo    repositories.UserRepository
+	gameService services.GameService
+	publisher   events.GameEventPublisher
+}
+
+// NewMatchmakingService creates a new instance of MatchmakingService
+func NewMatchmakingService(seekRepo repositories.SeekRepository, userRepo repositories.UserRepository, gameService services.GameService, publisher events.GameEventPublisher) services.MatchmakingService {
+	return &matchmakingService{
+		seekRepo:    seekRepo,
+		userRepo:    userRepo,
+		gameService: gameService,
+		publisher:   publisher,
+	}
+}
+
+// Seek queues a player and tries to pair them immediately
+func (s *matchmakingService) Seek(ctx context.Context, req services.SeekRequest) (*services.SeekResponse, error) {
+	if req.PlayerID.IsZero() {
+		return nil, errors.New("player ID is required")
+	}
+
+	// Replace any seek the player already has
+	if existing, err := s.seekRepo.FindByPlayerID(ctx, req.PlayerID); err == nil {
+		if existing.Status == matchmaking.SeekStatusSearching {
+			err = s.CancelSeek(ctx, req.PlayerID)
+		} else {
+			err = s.seekRepo.Delete(ctx, req.PlayerID)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Players are paired by their rating in the category of the time control
+	rating := matchmaking.UnratedRating
+	if req.TimeControl != nil {
+		player, err := s.userRepo.FindByID(ctx, req.PlayerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find player: %w", err)
+		}
+		rating = player.Rating(req.TimeControl.Category()).Rounded()
+	}
+
+	seek, err := matchmaking.NewSeek(req.PlayerID, req.TimeControl, req.Rated, rating)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create seek: %w", err)
+	}
+
+	if err := s.seekRepo.Save(ctx, seek); err != nil {
+		return nil, fmt.Errorf("failed to save seek: %w", err)
+	}
+
+	if _, err := s.matchQueue(ctx, seek.QueueKey()); err != nil {
+		log.Printf("Failed to match queue %s: %v", seek.QueueKey(), err)
+	}
+
+	return s.GetSeek(ctx, req.PlayerID)
+}
+
+// GetSeek retrieves the seek of a player
+func (s *matchmakingService) GetSeek(ctx context.Context, playerID primitive.ObjectID) (*services.SeekResponse, error) {
+	if playerID.IsZero() {
+		return nil, errors.New("player ID is required")
+	}
+
+	seek, err := s.seekRepo.FindByPlayerID(ctx, playerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find seek: %w", err)
+	}
+
+	return newSeekResponse(seek), nil
+}
+
+// CancelSeek takes a searching player out of the queue
+func (s *matchmakingService) CancelSeek(ctx context.Context, playerID primitive.ObjectID) error {
+	if playerID.IsZero() {
+		return errors.New("player ID is required")
+	}
+
+	seek, err := s.seekRepo.FindByPlayerID(ctx, playerID)
+	if err != nil {
+		return fmt.Errorf("failed to find seek: %w", err)
+	}
+	if seek.Status == matchmaking.SeekStatusMatched {
+		return fmt.Errorf("seek was already matched into game %s", seek.GameID.Hex())
+	}
+
+	// Claim the seek first so it cannot be paired while it is being cancelled
+	claimed, err := s.seekRepo.Claim(ctx, seek)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return errors.New("seek is being matched")
+	}
+
+	return s.seekRepo.Delete(ctx, playerID)
+}
+
+// MatchSeeks pairs waiting players in every queue
+func (s *matchmakingService) MatchSeeks(ctx context.Context) (int, error) {
+	queueKeys, err := s.seekRepo.Queues(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find queues: %w", err)
+	}
+
+	matched := 0
+	for _, queueKey := range queueKeys {
+		n, err := s.matchQueue(ctx, queueKey)
+		matched += n
+		if err != nil {
+			return matched, fmt.Errorf("failed to match queue %s: %w", queueKey, err)
+		}
+	}
+
+	return matched, nil
+}
+
+// matchQueue pairs neighbouring seeks of a queue, which is ordered by rating,
+// whenever they accept each other
+func (s *matchmakingService) matchQueue(ctx context.Context, queueKey string) (int, error) {
+	seeks, err := s.seekRepo.FindInQueue(ctx, queueKey)
+	if err != nil {
+		return 0, err
+	}
+
+	matched := 0
+	now := time.Now()
+	for i := 0; i+1 < len(seeks); i++ {
+		a, b := seeks[i], seeks[i+1]
+		if !a.Accepts(b, now) {
+			continue
+		}
+
+		ok, err := s.pair(ctx, a, b)
+		if err != nil {
+			return matched, err
+		}
+		if ok {
+			matched++
+			i++ // b is taken as well
+		}
+	}
+
+	return matched, nil
+}
+
+// pair claims both seeks and starts their game, rated if the seeks are, with colors
+// drawn at random. Both players are notified with a matched event. It reports
+// false if either seek was claimed elsewhere, in which case the other one is put
+// back in the queue.
+func (s *matchmakingService) pair(ctx context.Context, a, b *matchmaking.Seek) (bool, error) {
+	// The player who has waited longer is claimed first
+	if b.CreatedAt.Before(a.CreatedAt) {
+		a, b = b, a
+	}
+
+	claimedA, err := s.seekRepo.Claim(ctx, a)
+	if err != nil || !claimedA {
+		return false, err
+	}
+	claimedB, err := s.seekRepo.Claim(ctx, b)
+	if err != nil || !claimedB {
+		return false, errors.Join(err, s.seekRepo.Save(ctx, a))
+	}
+
+	white, black := a, b
+	if rand.IntN(2) == 0 {
+		white, black = b, a
+	}
+	started, err := s.gameService.StartGame(ctx, services.StartGameRequest{
+		WhitePlayerID: white.PlayerID,
+		BlackPlayerID: black.PlayerID,
+		TimeControl:   a.TimeControl,
+		Rated:         a.Rated,
+	})
+	if err != nil {
+		return false, errors.Join(fmt.Errorf("failed to start game: %w", err), s.requeue(ctx, a, b))
+	}
+
+	// Both players find the game on their seek, and are told about it
+	for _, seek := range []*matchmaking.Seek{a, b} {
+		if err := seek.Match(started.Game.ID); err != nil {
+			return true, err
+		}
+		if err := s.seekRepo.Save(ctx, seek); err != nil {
+			return true, fmt.Errorf("failed to save seek: %w", err)
+		}
+		s.notify(ctx, game.NewUserEvent(game.EventMatched, started.Game, seek.PlayerID))
+	}
+
+	return true, nil
+}
+
+// notify sends an event to a player. Failures are only logged: the player still
+// finds the game on their seek.
+func (s *matchmakingService) notify(ctx context.Context, event game.Event) {
+	if err := s.publisher.Publish(context.WithoutCancel(ctx), event); err != nil {
+		log.Printf("Failed to notify user %s of game %s: %v", event.UserID.Hex(), event.GameID.Hex(), err)
+	}
+}
+
+// requeue puts claimed seeks back in their queue after a failed pairing
+func (s *matchmakingService) requeue(ctx context.Context, seeks ...*matchmaking.Seek) error {
+	var errs []error
+	for _, seek := range seeks {
+		errs = append(errs, s.seekRepo.Save(ctx, seek))
+	}
+	return errors.Join(errs...)
+}
+
+// newSeekResponse builds the response for a seek
+func newSeekResponse(seek *matchmaking.Seek) *services.SeekResponse {
+	resp := &services.SeekResponse{
+		Message: "Searching for an opponent",
+		Seek:    seek,
+	}
+	if seek.Status == matchmaking.SeekStatusMatched {
+		resp.Message = "Opponent found"
+		resp.GameID = seek.GameID.Hex()
+	}
+	return resp
+}
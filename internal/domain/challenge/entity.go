@@ -0,0 +1,188 @@
+// Package challenge contains the Challenge domain entity and its business logic.
+// This is part of the Domain layer in Hexagonal Architecture.
+// Domain entities should be pure business logic without any external dependencies.
+package challenge
+
+import (
+	"errors"
+	"time"
+
+	"chess-backend/internal/domain/game"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ChallengeStatus represents the current status of a challenge
+type ChallengeStatus string
+
+const (
+	ChallengeStatusPending   ChallengeStatus = "pending"   // Waiting for the challenged player to respond
+	ChallengeStatusAccepted  ChallengeStatus = "accepted"  // Accepted, the game has been created
+	ChallengeStatusDeclined  ChallengeStatus = "declined"  // Declined by the challenged player
+	ChallengeStatusCancelled ChallengeStatus = "cancelled" // Withdrawn by the challenger
+	ChallengeStatusExpired   ChallengeStatus = "expired"   // Not answered in time
+)
+
+// Challenge is an invitation from one player to another to play a game
+type Challenge struct {
+	ID           primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	ChallengerID primitive.ObjectID   `bson:"challenger_id" json:"challenger_id"`
+	ChallengedID primitive.ObjectID   `bson:"challenged_id" json:"challenged_id"`
+	TimeControl  *game.TimeControl    `bson:"time_control,omitempty" json:"time_control,omitempty"` // nil for an untimed game
+	Color        game.ColorPreference `bson:"color" json:"color"`                                   // Color the challenger asked for
+	Rated        bool                 `bson:"rated" json:"rated"`
+	Status       ChallengeStatus      `bson:"status" json:"status"`
+	GameID       primitive.ObjectID   `bson:"game_id,omitempty" json:"game_id,omitempty"` // Set once accepted
+
+	CreatedAt   time.Time  `bson:"created_at" json:"created_at"`
+	ExpiresAt   time.Time  `bson:"expires_at" json:"expires_at"`
+	RespondedAt *time.Time `bson:"responded_at,omitempty" json:"responded_at,omitempty"`
+}
+
+// Options holds the game settings proposed by the challenger
+type Options struct {
+	TimeControl *game.TimeControl    // nil for an untimed game
+	Color       game.ColorPreference // Empty means random
+	Rated       bool                 // Rated games need a time control
+}
+
+// NewChallenge creates a challenge that expires if it is not answered within ttl
+func NewChallenge(challengerID, challengedID primitive.ObjectID, opts Options, ttl time.Duration) (*Challenge, error) {
+	if challengerID.IsZero() || challengedID.IsZero() {
+		return nil, errors.New("challenger and challenged player IDs cannot be empty")
+	}
+	if challengerID == challengedID {
+		return nil, errors.New("player cannot challenge themselves")
+	}
+	if ttl <= 0 {
+		return nil, errors.New("challenge TTL must be positive")
+	}
+
+	color := opts.Color
+	if color == "" {
+		color = game.ColorRandom
+	}
+	if err := color.Validate(); err != nil {
+		return nil, err
+	}
+	if opts.TimeControl != nil {
+		if err := opts.TimeControl.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Rated && opts.TimeControl == nil {
+		return nil, errors.New("rated games need a time control")
+	}
+
+	now := time.Now()
+	return &Challenge{
+		ID:           primitive.NewObjectID(),
+		ChallengerID: challengerID,
+		ChallengedID: challengedID,
+		TimeControl:  opts.TimeControl,
+		Color:        color,
+		Rated:        opts.Rated,
+		Status:       ChallengeStatusPending,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(ttl),
+	}, nil
+}
+
+// IsExpired reports whether a pending challenge was not answered in time
+func (c *Challenge) IsExpired(now time.Time) bool {
+	return c.Status == ChallengeStatusPending && !now.Before(c.ExpiresAt)
+}
+
+// Involves reports whether the user sent or received the challenge
+func (c *Challenge) Involves(userID primitive.ObjectID) bool {
+	return c.ChallengerID == userID || c.ChallengedID == userID
+}
+
+// Players returns the white and black players of the game the challenge leads to,
+// resolving the challenger's color preference
+func (c *Challenge) Players() (white, black primitive.ObjectID, err error) {
+	color, err := c.Color.Resolve()
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, err
+	}
+	if color == "white" {
+		return c.ChallengerID, c.ChallengedID, nil
+	}
+	return c.ChallengedID, c.ChallengerID, nil
+}
+
+// CheckAccept verifies that the user may accept the challenge now
+func (c *Challenge) CheckAccept(userID primitive.ObjectID) error {
+	if c.ChallengedID != userID {
+		return errors.New("only the challenged player can accept the challenge")
+	}
+	return c.checkPending()
+}
+
+// Accept marks the challenge as accepted into the given game
+func (c *Challenge) Accept(userID, gameID primitive.ObjectID) error {
+	if err := c.CheckAccept(userID); err != nil {
+		return err
+	}
+	if gameID.IsZero() {
+		return errors.New("game ID cannot be empty")
+	}
+
+	c.GameID = gameID
+	c.respond(ChallengeStatusAccepted)
+	return nil
+}
+
+// Decline lets the challenged player turn the challenge down
+func (c *Challenge) Decline(userID primitive.ObjectID) error {
+	if c.ChallengedID != userID {
+		return errors.New("only the challenged player can decline the challenge")
+	}
+	if err := c.checkPending(); err != nil {
+		return err
+	}
+
+	c.respond(ChallengeStatusDeclined)
+	return nil
+}
+
+// Cancel lets the challenger withdraw the challenge
+func (c *Challenge) Cancel(userID primitive.ObjectID) error {
+	if c.ChallengerID != userID {
+		return errors.New("only the challenger can cancel the challenge")
+	}
+	if err := c.checkPending(); err != nil {
+		return err
+	}
+
+	c.respond(ChallengeStatusCancelled)
+	return nil
+}
+
+// Expire marks a pending challenge that was not answered in time as expired.
+// It reports whether the challenge was expired.
+func (c *Challenge) Expire(now time.Time) bool {
+	if !c.IsExpired(now) {
+		return false
+	}
+	c.Status = ChallengeStatusExpired
+	return true
+}
+
+// checkPending verifies that the challenge can still be answered
+func (c *Challenge) checkPending() error {
+	if c.Status != ChallengeStatusPending {
+		return errors.New("challenge is no longer pending")
+	}
+	if c.IsExpired(time.Now()) {
+		return errors.New("challenge has expired")
+	}
+	return nil
+}
+
+// respond records the answer to the challenge
+func (c *Challenge) respond(status ChallengeStatus) {
+	now := time.Now()
+	c.Status = status
+	c.RespondedAt = &now
+}
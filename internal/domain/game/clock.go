@@ -0,0 +1,296 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"chess-backend/internal/domain/rating"
+)
+
+// ErrTimeExpired is returned when a player tries to move after their clock ran out.
+// The game is finished on time when this error is returned.
+var ErrTimeExpired = errors.New("time has expired")
+
+// TimeControlMode selects how the clocks are run
+type TimeControlMode string
+
+const (
+	TimeControlIncrement      TimeControlMode = "increment"      // Fischer increment added after every move
+	TimeControlBronstein      TimeControlMode = "bronstein"      // Time used is refunded after the move, up to the delay
+	TimeControlSimpleDelay    TimeControlMode = "delay"          // Clock only starts running after the delay
+	TimeControlCorrespondence TimeControlMode = "correspondence" // A fixed number of days for every move
+)
+
+// Limits for time controls chosen at game creation
+const (
+	maxBaseMinutes      = 180
+	maxIncrementSeconds = 60
+	maxDelaySeconds     = 60
+	maxDaysPerMove      = 14
+)
+
+// TimeControl describes the clock settings of a game: a base time per player plus an
+// increment or delay depending on the mode, or a number of days per move for
+// correspondence games. An empty mode means increment.
+type TimeControl struct {
+	Mode             TimeControlMode `bson:"mode,omitempty" json:"mode,omitempty"`
+	BaseMinutes      int             `bson:"base_minutes,omitempty" json:"base_minutes,omitempty"`
+	IncrementSeconds int             `bson:"increment_seconds,omitempty" json:"increment_seconds,omitempty"`
+	DelaySeconds     int             `bson:"delay_seconds,omitempty" json:"delay_seconds,omitempty"`
+	DaysPerMove      int             `bson:"days_per_move,omitempty" json:"days_per_move,omitempty"`
+}
+
+// Validate checks that the time control is within the supported limits
+func (tc *TimeControl) Validate() error {
+	switch tc.Mode {
+	case "", TimeControlIncrement:
+		if tc.DelaySeconds != 0 || tc.DaysPerMove != 0 {
+			return errors.New("increment time control takes only base time and increment")
+		}
+		if tc.IncrementSeconds < 0 || tc.IncrementSeconds > maxIncrementSeconds {
+			return errors.New("increment must be between 0 and 60 seconds")
+		}
+	case TimeControlBronstein, TimeControlSimpleDelay:
+		if tc.IncrementSeconds != 0 || tc.DaysPerMove != 0 {
+			return errors.New("delay time control takes only base time and delay")
+		}
+		if tc.DelaySeconds <= 0 || tc.DelaySeconds > maxDelaySeconds {
+			return errors.New("delay must be between 1 and 60 seconds")
+		}
+	case TimeControlCorrespondence:
+		if tc.BaseMinutes != 0 || tc.IncrementSeconds != 0 || tc.DelaySeconds != 0 {
+			return errors.New("correspondence time control takes only days per move")
+		}
+		if tc.DaysPerMove <= 0 || tc.DaysPerMove > maxDaysPerMove {
+			return errors.New("days per move must be between 1 and 14")
+		}
+		return nil
+	default:
+		return errors.New("unknown time control mode")
+	}
+
+	if tc.BaseMinutes <= 0 || tc.BaseMinutes > maxBaseMinutes {
+		return errors.New("base time must be between 1 and 180 minutes")
+	}
+	return nil
+}
+
+// Key identifies the time control, e.g. "increment:5+3", "delay:15+5" or
+// "correspondence:3", so games with the same settings can be grouped
+func (tc *TimeControl) Key() string {
+	mode := tc.Mode
+	if mode == "" {
+		mode = TimeControlIncrement
+	}
+	switch mode {
+	case TimeControlCorrespondence:
+		return fmt.Sprintf("%s:%d", mode, tc.DaysPerMove)
+	case TimeControlBronstein, TimeControlSimpleDelay:
+		return fmt.Sprintf("%s:%d+%d", mode, tc.BaseMinutes, tc.DelaySeconds)
+	default:
+		return fmt.Sprintf("%s:%d+%d", mode, tc.BaseMinutes, tc.IncrementSeconds)
+	}
+}
+
+// Category returns the rating category of the time control, based on the expected
+// duration of a game of forty moves per player
+func (tc *TimeControl) Category() rating.Category {
+	if tc.IsCorrespondence() {
+		return rating.CategoryCorrespondence
+	}
+
+	perMove := tc.IncrementSeconds + tc.DelaySeconds
+	estimated := time.Duration(tc.BaseMinutes)*time.Minute + time.Duration(40*perMove)*time.Second
+	switch {
+	case estimated < 3*time.Minute:
+		return rating.CategoryBullet
+	case estimated < 8*time.Minute:
+		return rating.CategoryBlitz
+	case estimated < 25*time.Minute:
+		return rating.CategoryRapid
+	default:
+		return rating.CategoryClassical
+	}
+}
+
+// IsCorrespondence reports whether moves are due by a calendar deadline
+func (tc *TimeControl) IsCorrespondence() bool {
+	return tc.Mode == TimeControlCorrespondence
+}
+
+// base returns the starting time of each player, or the time for each move in correspondence
+func (tc *TimeControl) base() time.Duration {
+	if tc.IsCorrespondence() {
+		return time.Duration(tc.DaysPerMove) * 24 * time.Hour
+	}
+	return time.Duration(tc.BaseMinutes) * time.Minute
+}
+
+// delay returns the delay of the bronstein and simple delay modes
+func (tc *TimeControl) delay() time.Duration {
+	return time.Duration(tc.DelaySeconds) * time.Second
+}
+
+// running returns the time left while a player is thinking, before the move is made
+func (tc *TimeControl) running(remaining, elapsed time.Duration) time.Duration {
+	if tc.Mode == TimeControlSimpleDelay {
+		return remaining - max(0, elapsed-tc.delay())
+	}
+	return remaining - elapsed
+}
+
+// charge returns a player's remaining time after completing a move that took elapsed
+func (tc *TimeControl) charge(remaining, elapsed time.Duration) time.Duration {
+	switch tc.Mode {
+	case TimeControlBronstein:
+		return remaining - elapsed + min(elapsed, tc.delay())
+	case TimeControlSimpleDelay:
+		return tc.running(remaining, elapsed)
+	case TimeControlCorrespondence:
+		// Every move gets the full allowance again
+		return tc.base()
+	default:
+		return remaining - elapsed + time.Duration(tc.IncrementSeconds)*time.Second
+	}
+}
+
+// Clock holds the remaining time of each player. The remaining time of the side to
+// move is as of TurnStartedAt; the time elapsed since then has not been deducted yet.
+type Clock struct {
+	WhiteRemainingMs int64      `bson:"white_remaining_ms" json:"white_remaining_ms"`
+	BlackRemainingMs int64      `bson:"black_remaining_ms" json:"black_remaining_ms"`
+	TurnStartedAt    *time.Time `bson:"turn_started_at,omitempty" json:"turn_started_at,omitempty"`
+}
+
+// newClock creates a clock with the base time on both sides
+func newClock(tc *TimeControl) *Clock {
+	base := tc.base().Milliseconds()
+	return &Clock{
+		WhiteRemainingMs: base,
+		BlackRemainingMs: base,
+	}
+}
+
+// remaining returns the stored remaining time of a side
+func (c *Clock) remaining(color string) time.Duration {
+	if color == "white" {
+		return time.Duration(c.WhiteRemainingMs) * time.Millisecond
+	}
+	return time.Duration(c.BlackRemainingMs) * time.Millisecond
+}
+
+// setRemaining stores the remaining time of a side, never below zero
+func (c *Clock) setRemaining(color string, d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	if color == "white" {
+		c.WhiteRemainingMs = d.Milliseconds()
+	} else {
+		c.BlackRemainingMs = d.Milliseconds()
+	}
+}
+
+// start starts the clock of the side to move
+func (c *Clock) start(at time.Time) {
+	c.TurnStartedAt = &at
+}
+
+// elapsed returns how long the side to move has been thinking
+func (c *Clock) elapsed(now time.Time) time.Duration {
+	if c.TurnStartedAt == nil {
+		return 0
+	}
+	return now.Sub(*c.TurnStartedAt)
+}
+
+// RemainingTime returns the time a player has left at the given moment, counting the
+// running clock of the side to move. Untimed games return zero.
+func (g *Game) RemainingTime(color string, now time.Time) time.Duration {
+	if g.Clock == nil {
+		return 0
+	}
+	remaining := g.Clock.remaining(color)
+	if g.Status == GameStatusActive && color == g.CurrentTurn && g.TimeControl != nil {
+		remaining = g.TimeControl.running(remaining, g.Clock.elapsed(now))
+	}
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// MoveDeadline returns the moment the side to move runs out of time, assuming the
+// clock keeps running. It is nil for untimed games and games that are not in progress.
+func (g *Game) MoveDeadline() *time.Time {
+	if !g.IsTimed() || g.Status != GameStatusActive || g.Clock.TurnStartedAt == nil {
+		return nil
+	}
+	deadline := g.Clock.TurnStartedAt.Add(g.Clock.remaining(g.CurrentTurn))
+	if g.TimeControl.Mode == TimeControlSimpleDelay {
+		deadline = deadline.Add(g.TimeControl.delay())
+	}
+	return &deadline
+}
+
+// IsTimed reports whether the game is played with a clock
+func (g *Game) IsTimed() bool {
+	return g.TimeControl != nil && g.Clock != nil
+}
+
+// IsCorrespondence reports whether the game is a correspondence game
+func (g *Game) IsCorrespondence() bool {
+	return g.TimeControl != nil && g.TimeControl.IsCorrespondence()
+}
+
+// flagged reports whether the side to move has run out of time
+func (g *Game) flagged(now time.Time) bool {
+	return g.IsTimed() && g.Status == GameStatusActive && g.RemainingTime(g.CurrentTurn, now) <= 0
+}
+
+// CheckFlag finishes the game on time if the side to move has run out of time.
+// It reports whether the game was finished.
+func (g *Game) CheckFlag(now time.Time) bool {
+	if !g.flagged(now) {
+		return false
+	}
+	g.finishOnTime()
+	return true
+}
+
+// finishOnTime ends the game as a loss for the side to move, whose clock ran out.
+// The game is drawn instead when the opponent has no material to checkmate with.
+func (g *Game) finishOnTime() {
+	g.Clock.setRemaining(g.CurrentTurn, 0)
+	winner := white
+	if g.CurrentTurn == "white" {
+		winner = black
+	}
+
+	if pos, err := g.position(); err == nil && !pos.hasMatingMaterial(winner) {
+		g.finish(GameResultDraw, TerminationTimeout)
+		return
+	}
+	g.finish(winnerResult(winner.String()), TerminationTimeout)
+}
+
+// rewindClock restores the remaining time of both players from the moves left after
+// a takeback and starts the clock of the side to move
+func (g *Game) rewindClock(now time.Time) {
+	g.Clock.setRemaining("white", g.TimeControl.base())
+	g.Clock.setRemaining("black", g.TimeControl.base())
+	for _, mv := range g.Moves {
+		g.Clock.setRemaining(mv.Player, time.Duration(mv.ClockMs)*time.Millisecond)
+	}
+	g.Clock.start(now)
+}
+
+// chargeClock deducts the thinking time of the side that just moved according to the
+// time control mode. It returns the mover's remaining time after the move.
+func (g *Game) chargeClock(color string, movedAt time.Time) time.Duration {
+	remaining := g.TimeControl.charge(g.Clock.remaining(color), g.Clock.elapsed(movedAt))
+	g.Clock.setRemaining(color, remaining)
+	g.Clock.start(movedAt)
+	return g.Clock.remaining(color)
+}
@@ -0,0 +1,156 @@
+package game
+
+import "strconv"
+
+// Draw rule thresholds, counted in plies (halfmoves) for the move rules
+const (
+	fiftyMoveRulePlies       = 100 // Claimable after fifty moves by each side without a capture or pawn move
+	seventyFiveMoveRulePlies = 150 // Automatic after seventy-five moves by each side
+	threefoldRepetition      = 3   // Claimable
+	fivefoldRepetition       = 5   // Automatic
+)
+
+// zobristKeys holds the random keys used to hash positions
+type zobristKeys struct {
+	pieces      [2][7][64]uint64
+	castling    [16]uint64
+	epFile      [8]uint64
+	blackToMove uint64
+}
+
+// zobrist is generated from a fixed seed so hashes stay stable across restarts
+// and can be stored with the game
+var zobrist = func() *zobristKeys {
+	state := uint64(0x9E3779B97F4A7C15)
+	next := func() uint64 {
+		// splitmix64
+		state += 0x9E3779B97F4A7C15
+		z := state
+		z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+		z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+		return z ^ (z >> 31)
+	}
+
+	keys := &zobristKeys{}
+	for c := range keys.pieces {
+		for kind := range keys.pieces[c] {
+			for sq := range keys.pieces[c][kind] {
+				keys.pieces[c][kind][sq] = next()
+			}
+		}
+	}
+	for i := range keys.castling {
+		keys.castling[i] = next()
+	}
+	for i := range keys.epFile {
+		keys.epFile[i] = next()
+	}
+	keys.blackToMove = next()
+	return keys
+}()
+
+// hash returns the Zobrist hash identifying the position for repetition purposes.
+// Two positions are the same when the same pieces stand on the same squares, the
+// same side is to move and the same castling and en-passant captures are possible,
+// so the en-passant square only counts when a capture onto it is legal.
+func (p *Position) hash() uint64 {
+	var h uint64
+	for sq, pc := range p.board {
+		if !pc.isEmpty() {
+			h ^= zobrist.pieces[pc.color][pc.kind][sq]
+		}
+	}
+	h ^= zobrist.castling[p.castling]
+	if p.turn == black {
+		h ^= zobrist.blackToMove
+	}
+	if p.epSquare != noSquare {
+		for _, m := range p.legalMoves() {
+			if m.flags&flagEnPassant != 0 {
+				h ^= zobrist.epFile[fileOf(p.epSquare)]
+				break
+			}
+		}
+	}
+	return h
+}
+
+// hashKey formats a position hash for storage
+func hashKey(h uint64) string {
+	return strconv.FormatUint(h, 16)
+}
+
+// insufficientMaterial reports whether neither side can possibly deliver mate:
+// king against king, king and a single minor piece against king, or kings
+// with any number of bishops that all stand on squares of the same color
+func (p *Position) insufficientMaterial() bool {
+	minors := 0
+	knights := 0
+	bishopSquareColors := [2]bool{}
+	for sq, pc := range p.board {
+		switch pc.kind {
+		case pawn, rook, queen:
+			return false
+		case knight:
+			minors++
+			knights++
+		case bishop:
+			minors++
+			bishopSquareColors[(fileOf(sq)+rankOf(sq))%2] = true
+		}
+	}
+
+	if minors <= 1 {
+		return true
+	}
+	return knights == 0 && !(bishopSquareColors[0] && bishopSquareColors[1])
+}
+
+// hasMatingMaterial reports whether a side could checkmate by any sequence of legal
+// moves, counting on the opponent's own pieces to block their king, as lichess does
+// for games lost on time. A lone knight mates with the help of any opponent piece but
+// a queen; bishops on squares of one color only with the help of an opponent pawn or
+// knight, or of a bishop on the other color.
+func (p *Position) hasMatingMaterial(c color) bool {
+	knights, bishops := 0, 0
+	bishopSquareColors := [2]bool{} // Of every bishop on the board
+	opponentPawnsOrKnights := false
+	opponentBlockers := false // Opponent pieces other than the king and queens
+	for sq, pc := range p.board {
+		if pc.isEmpty() {
+			continue
+		}
+		if pc.kind == bishop {
+			bishopSquareColors[(fileOf(sq)+rankOf(sq))%2] = true
+		}
+		if pc.color != c {
+			switch pc.kind {
+			case pawn, knight:
+				opponentPawnsOrKnights = true
+				opponentBlockers = true
+			case bishop, rook:
+				opponentBlockers = true
+			}
+			continue
+		}
+		switch pc.kind {
+		case pawn, rook, queen:
+			return true
+		case knight:
+			knights++
+		case bishop:
+			bishops++
+		}
+	}
+
+	switch {
+	case knights == 0 && bishops == 0:
+		return false
+	case knights == 1 && bishops == 0:
+		return opponentBlockers
+	case knights == 0:
+		return (bishopSquareColors[0] && bishopSquareColors[1]) || opponentPawnsOrKnights
+	default:
+		return true
+	}
+}
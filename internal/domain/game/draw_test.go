@@ -0,0 +1,42 @@
+package game
+
+import "testing"
+
+// matingMaterialCases decide whether the side that did not run out of time can still
+// win, possibly with the help of the opponent's pieces
+var matingMaterialCases = []struct {
+	name string
+	fen  string
+	side color
+	want bool
+}{
+	{"bare king", "4k3/8/8/8/8/8/8/4K3 w - - 0 1", white, false},
+	{"rook", "4k3/8/8/8/8/8/8/R3K3 w - - 0 1", white, true},
+	{"lone knight", "4k3/8/8/8/8/8/8/4K1N1 w - - 0 1", white, false},
+	{"knight against pawn", "4k3/4p3/8/8/8/8/8/4K1N1 w - - 0 1", white, true},
+	{"knight against rook", "r3k3/8/8/8/8/8/8/4K1N1 w - - 0 1", white, true},
+	{"knight against queen", "q3k3/8/8/8/8/8/8/4K1N1 w - - 0 1", white, false},
+	{"two knights", "4k3/8/8/8/8/8/8/1N2K1N1 w - - 0 1", white, true},
+	{"lone bishop", "4k3/8/8/8/8/8/8/2B1K3 w - - 0 1", white, false},
+	{"bishop against knight", "4k1n1/8/8/8/8/8/8/2B1K3 w - - 0 1", white, true},
+	{"bishop against rook", "r3k3/8/8/8/8/8/8/2B1K3 w - - 0 1", white, false},
+	{"bishops on one color", "4k3/8/8/8/8/4B3/8/2B1K3 w - - 0 1", white, false},
+	{"bishops on both colors", "4k3/8/8/8/8/8/8/2B1KB2 w - - 0 1", white, true},
+	{"bishop against bishop on the other color", "2b1k3/8/8/8/8/8/8/2B1K3 w - - 0 1", white, true},
+	{"bishop against bishop on the same color", "4kb2/8/8/8/8/8/8/2B1K3 w - - 0 1", white, false},
+	{"black knight against pawn", "4k1n1/8/8/8/8/8/4P3/4K3 b - - 0 1", black, true},
+}
+
+func TestHasMatingMaterial(t *testing.T) {
+	for _, tc := range matingMaterialCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pos, err := ParseFEN(tc.fen)
+			if err != nil {
+				t.Fatalf("ParseFEN(%q): %v", tc.fen, err)
+			}
+			if got := pos.hasMatingMaterial(tc.side); got != tc.want {
+				t.Errorf("hasMatingMaterial = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -4,9 +4,16 @@
 package game
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	mathrand "math/rand/v2"
 	"time"
 
+	"chess-backend/internal/domain/rating"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -18,8 +25,25 @@ const (
 	GameStatusActive    GameStatus = "active"    // Game in progress
 	GameStatusFinished  GameStatus = "finished"  // Game completed
 	GameStatusAbandoned GameStatus = "abandoned" // Game abandoned
+	GameStatusAborted   GameStatus = "aborted"   // Called off before both players moved, does not count
 )
 
+// AbandonmentClaim is what a player claims when the opponent has abandoned the game
+type AbandonmentClaim string
+
+const (
+	ClaimVictory AbandonmentClaim = "win"
+	ClaimDraw    AbandonmentClaim = "draw"
+)
+
+// minAbandonmentGrace is the shortest time an opponent must have been gone before
+// their game can be claimed, however fast the time control
+const minAbandonmentGrace = time.Minute
+
+// abortablePlies is the number of moves before which a game can still be aborted:
+// until both players have made their first move
+const abortablePlies = 2
+
 // GameResult represents the result of a finished game
 type GameResult string
 
@@ -30,6 +54,24 @@ const (
 	GameResultAbandoned GameResult = "abandoned"
 )
 
+// Termination describes how a game came to an end
+type Termination string
+
+const (
+	TerminationCheckmate   Termination = "checkmate"
+	TerminationStalemate   Termination = "stalemate"
+	TerminationResignation Termination = "resignation"
+	TerminationTimeout     Termination = "timeout"
+	TerminationAgreement   Termination = "agreement"
+	TerminationAbandonment Termination = "abandonment"
+
+	TerminationThreefoldRepetition  Termination = "threefold_repetition"
+	TerminationFivefoldRepetition   Termination = "fivefold_repetition"
+	TerminationFiftyMoveRule        Termination = "fifty_move_rule"
+	TerminationSeventyFiveMoveRule  Termination = "seventy_five_move_rule"
+	TerminationInsufficientMaterial Termination = "insufficient_material"
+)
+
 // Move represents a chess move
 type Move struct {
 	From      string    `bson:"from" json:"from"`           // e.g., "e2"
@@ -37,94 +79,349 @@ type Move struct {
 	Piece     string    `bson:"piece" json:"piece"`         // e.g., "pawn", "king"
 	Player    string    `bson:"player" json:"player"`       // "white" or "black"
 	Timestamp time.Time `bson:"timestamp" json:"timestamp"`
-	Notation  string    `bson:"notation" json:"notation"`   // Algebraic notation
+	Notation  string    `bson:"notation" json:"notation"`   // Algebraic notation, same as SAN
+
+	// Move properties computed by the server
+	SAN       string `bson:"san" json:"san"`                                 // Standard algebraic notation, e.g. "Nf3"
+	UCI       string `bson:"uci" json:"uci"`                                 // UCI notation, e.g. "g1f3"
+	Promotion string `bson:"promotion,omitempty" json:"promotion,omitempty"` // Piece a pawn promoted to, e.g. "queen"
+	Capture   bool   `bson:"capture" json:"capture"`
+	Castle    bool   `bson:"castle" json:"castle"`
+	EnPassant bool   `bson:"en_passant" json:"en_passant"`
+	Check     bool   `bson:"check" json:"check"`
+	Checkmate bool   `bson:"checkmate" json:"checkmate"`
+	ClockMs   int64  `bson:"clock_ms,omitempty" json:"clock_ms,omitempty"` // Mover's remaining time after the move
+
+	PositionHash string `bson:"position_hash" json:"-"` // Hash of the position after the move, for repetition checks
+}
+
+// RatingChange records how a player's rating moved as a result of a rated game
+type RatingChange struct {
+	Category rating.Category `bson:"category" json:"category"`
+	Before   int             `bson:"before" json:"before"`
+	After    int             `bson:"after" json:"after"`
+	Change   int             `bson:"change" json:"change"`
+}
+
+// LegalMove describes a move available to the side to move
+type LegalMove struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Promotion string `json:"promotion,omitempty"`
+	UCI       string `json:"uci"`
+	SAN       string `json:"san"`
 }
 
 // Game represents a chess game entity in the domain
 type Game struct {
 	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	WhitePlayer primitive.ObjectID `bson:"white_player" json:"white_player"`
+	CreatedBy   primitive.ObjectID `bson:"created_by,omitempty" json:"created_by,omitempty"`
+	WhitePlayer primitive.ObjectID `bson:"white_player,omitempty" json:"white_player,omitempty"`
 	BlackPlayer primitive.ObjectID `bson:"black_player,omitempty" json:"black_player,omitempty"`
 	Status      GameStatus         `bson:"status" json:"status"`
 	Result      GameResult         `bson:"result,omitempty" json:"result,omitempty"`
+	Termination Termination        `bson:"termination,omitempty" json:"termination,omitempty"`
 	CurrentTurn string             `bson:"current_turn" json:"current_turn"` // "white" or "black"
 	Moves       []Move             `bson:"moves" json:"moves"`
-	Board       string             `bson:"board" json:"board"` // FEN notation
-	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
-	FinishedAt  *time.Time         `bson:"finished_at,omitempty" json:"finished_at,omitempty"`
+	MaxPly      int                `bson:"max_ply" json:"-"` // Most moves the game ever had, takebacks do not lower it
+	Board       string             `bson:"board" json:"board"` // FEN of the current position
+	TimeControl *TimeControl       `bson:"time_control,omitempty" json:"time_control,omitempty"`
+	Clock       *Clock             `bson:"clock,omitempty" json:"clock,omitempty"`
+
+	AllowSpectators bool `bson:"allow_spectators" json:"allow_spectators"`
+	SpectatorCount  int  `bson:"-" json:"spectator_count"` // Users currently watching, filled in by the service
+
+	AllowTakebacks  bool             `bson:"allow_takebacks" json:"allow_takebacks"`
+	TakebackRequest *TakebackRequest `bson:"takeback_request" json:"takeback_request,omitempty"` // Pending takeback request, if any
+
+	DrawOffer  *DrawOffer  `bson:"draw_offer" json:"draw_offer,omitempty"` // Pending draw offer, if any
+	DrawOffers []DrawOffer `bson:"draw_offers,omitempty" json:"-"`         // Every draw offer made, to limit them
+
+	Private    bool   `bson:"private" json:"private"`
+	InviteCode string `bson:"invite_code,omitempty" json:"-"` // Needed to join a private game, only given to the creator
+
+	Rated         bool                    `bson:"rated" json:"rated"`
+	RatingChanges map[string]RatingChange `bson:"rating_changes,omitempty" json:"rating_changes,omitempty"` // Keyed by color, set when a rated game finishes
+
+	CreatedAt  time.Time  `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time  `bson:"updated_at" json:"updated_at"`
+	StartedAt  *time.Time `bson:"started_at,omitempty" json:"started_at,omitempty"`
+	FinishedAt *time.Time `bson:"finished_at,omitempty" json:"finished_at,omitempty"`
+
+	// Version counts the saved updates, so an update based on an outdated copy of the
+	// game is detected instead of overwriting the newer one
+	Version int64 `bson:"version" json:"-"`
 }
 
-// NewGame creates a new chess game with white player
-func NewGame(whitePlayerID primitive.ObjectID) (*Game, error) {
-	if whitePlayerID.IsZero() {
-		return nil, errors.New("white player ID cannot be empty")
+// ColorPreference is the color a player asks for when setting up a game
+type ColorPreference string
+
+const (
+	ColorWhite  ColorPreference = "white"
+	ColorBlack  ColorPreference = "black"
+	ColorRandom ColorPreference = "random"
+)
+
+// Validate checks that the preference is one of the known colors
+func (p ColorPreference) Validate() error {
+	switch p {
+	case ColorWhite, ColorBlack, ColorRandom:
+		return nil
+	default:
+		return errors.New("color must be white, black or random")
+	}
+}
+
+// Resolve returns the color the player gets, "white" or "black", drawing one
+// at random for ColorRandom
+func (p ColorPreference) Resolve() (string, error) {
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+	if p == ColorRandom {
+		if mathrand.IntN(2) == 0 {
+			return "white", nil
+		}
+		return "black", nil
+	}
+	return string(p), nil
+}
+
+// GameOptions holds the settings chosen when a game is created
+type GameOptions struct {
+	TimeControl     *TimeControl    // nil for an untimed game
+	AllowSpectators *bool           // nil allows spectators
+	Rated           bool            // Rated games need a time control
+	Color           ColorPreference // Color of the creator, white when empty
+	Private         bool            // Private games can only be joined with the invite code
+	AllowTakebacks  *bool           // nil allows takebacks in unrated games only
+}
+
+// NewGame creates a new chess game with the creator seated on the color they asked for
+func NewGame(creatorID primitive.ObjectID, opts GameOptions) (*Game, error) {
+	if creatorID.IsZero() {
+		return nil, errors.New("creator ID cannot be empty")
+	}
+
+	preference := opts.Color
+	if preference == "" {
+		preference = ColorWhite
+	}
+	color, err := preference.Resolve()
+	if err != nil {
+		return nil, err
 	}
 
 	now := time.Now()
-	return &Game{
+	g := &Game{
 		ID:          primitive.NewObjectID(),
-		WhitePlayer: whitePlayerID,
+		CreatedBy:   creatorID,
 		Status:      GameStatusWaiting,
 		CurrentTurn: "white",
 		Moves:       []Move{},
-		Board:       "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", // Starting FEN
+		Board:       StartingFEN,
 		CreatedAt:   now,
 		UpdatedAt:   now,
-	}, nil
+
+		AllowSpectators: opts.AllowSpectators == nil || *opts.AllowSpectators,
+	}
+	if color == "white" {
+		g.WhitePlayer = creatorID
+	} else {
+		g.BlackPlayer = creatorID
+	}
+
+	if opts.Private {
+		code, err := generateInviteCode()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate invite code: %w", err)
+		}
+		g.Private = true
+		g.InviteCode = code
+	}
+
+	if opts.Rated {
+		if opts.TimeControl == nil {
+			return nil, errors.New("rated games need a time control")
+		}
+		g.Rated = true
+	}
+	g.AllowTakebacks = !g.Rated
+	if opts.AllowTakebacks != nil {
+		g.AllowTakebacks = *opts.AllowTakebacks
+	}
+
+	if opts.TimeControl != nil {
+		if err := opts.TimeControl.Validate(); err != nil {
+			return nil, err
+		}
+		tc := *opts.TimeControl
+		if tc.Mode == "" {
+			tc.Mode = TimeControlIncrement
+		}
+		g.TimeControl = &tc
+		g.Clock = newClock(&tc)
+	}
+
+	return g, nil
+}
+
+// NewGameBetween creates a game with both players already seated. The game starts
+// right away, so white's clock is running when it is returned.
+func NewGameBetween(whitePlayerID, blackPlayerID primitive.ObjectID, opts GameOptions) (*Game, error) {
+	opts.Color = ColorWhite
+	g, err := NewGame(whitePlayerID, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.JoinGame(blackPlayerID, g.InviteCode); err != nil {
+		return nil, err
+	}
+	return g, nil
 }
 
-// JoinGame allows a second player to join the game
-func (g *Game) JoinGame(blackPlayerID primitive.ObjectID) error {
-	if blackPlayerID.IsZero() {
-		return errors.New("black player ID cannot be empty")
+// JoinGame seats a second player on whichever color is still free. Private games
+// also need the invite code; it is ignored for public games.
+func (g *Game) JoinGame(playerID primitive.ObjectID, inviteCode string) error {
+	if playerID.IsZero() {
+		return errors.New("player ID cannot be empty")
 	}
 	if g.Status != GameStatusWaiting {
 		return errors.New("game is not waiting for players")
 	}
-	if g.WhitePlayer == blackPlayerID {
+	if g.IsPlayerInGame(playerID) {
 		return errors.New("player cannot play against themselves")
 	}
+	if g.Private && subtle.ConstantTimeCompare([]byte(inviteCode), []byte(g.InviteCode)) != 1 {
+		return errors.New("invalid invite code")
+	}
 
-	g.BlackPlayer = blackPlayerID
+	if g.WhitePlayer.IsZero() {
+		g.WhitePlayer = playerID
+	} else {
+		g.BlackPlayer = playerID
+	}
+	now := time.Now()
 	g.Status = GameStatusActive
-	g.UpdatedAt = time.Now()
+	g.UpdatedAt = now
+	g.StartedAt = &now
+
+	// White's clock starts as soon as the game begins
+	if g.IsTimed() {
+		g.Clock.start(g.UpdatedAt)
+	}
 	return nil
 }
 
-// MakeMove adds a move to the game
-func (g *Game) MakeMove(playerID primitive.ObjectID, from, to, piece, notation string) error {
+// MakeMove validates a move given as from/to squares and adds it to the game.
+// Pawns reaching the last rank promote to the requested piece, or a queen if none is given.
+// Moves that break the rules of chess are rejected with an *IllegalMoveError.
+// A move that checkmates or stalemates the opponent finishes the game, as does
+// reaching a position covered by an automatic draw rule.
+func (g *Game) MakeMove(playerID primitive.ObjectID, from, to, promotion string) error {
+	pos, err := g.positionForMove(playerID)
+	if err != nil {
+		return err
+	}
+
+	// Validate the move against the rules
+	promotionKind, err := parsePromotion(promotion)
+	if err != nil {
+		return &IllegalMoveError{Move: from + to + promotion, Reason: err.Error()}
+	}
+	m, err := pos.findMove(from, to, promotionKind)
+	if err != nil {
+		return err
+	}
+
+	g.playMove(pos, m)
+	return nil
+}
+
+// MakeMoveNotation validates a move written in UCI ("g1f3") or SAN ("Nf3") and adds it
+// to the game. Malformed, ambiguous and illegal moves are rejected with an *IllegalMoveError.
+func (g *Game) MakeMoveNotation(playerID primitive.ObjectID, notation string) error {
+	pos, err := g.positionForMove(playerID)
+	if err != nil {
+		return err
+	}
+
+	// Validate the move against the rules
+	m, err := pos.parseMove(notation)
+	if err != nil {
+		return err
+	}
+
+	g.playMove(pos, m)
+	return nil
+}
+
+// positionForMove checks that the player may move now and returns the current position.
+// If the player's clock has run out the game is finished on time and ErrTimeExpired is returned.
+func (g *Game) positionForMove(playerID primitive.ObjectID) (*Position, error) {
 	if g.Status != GameStatusActive {
-		return errors.New("game is not active")
+		return nil, errors.New("game is not active")
 	}
 
 	// Validate it's the player's turn
-	var expectedPlayer string
 	if g.CurrentTurn == "white" && g.WhitePlayer != playerID {
-		return errors.New("it's not your turn")
+		return nil, errors.New("it's not your turn")
 	}
 	if g.CurrentTurn == "black" && g.BlackPlayer != playerID {
-		return errors.New("it's not your turn")
+		return nil, errors.New("it's not your turn")
 	}
 
-	if g.CurrentTurn == "white" {
-		expectedPlayer = "white"
-	} else {
-		expectedPlayer = "black"
+	if g.flagged(time.Now()) {
+		g.finishOnTime()
+		return nil, ErrTimeExpired
 	}
 
+	return g.position()
+}
+
+// playMove records a legal move, updates the board and finishes the game if the move ends it
+func (g *Game) playMove(pos *Position, m move) {
+	expectedPlayer := pos.turn.String()
+	next := pos.play(m)
+	replies := next.legalMoves()
+	san := pos.san(m)
+	now := time.Now()
+
 	// Create the move
 	move := Move{
-		From:      from,
-		To:        to,
-		Piece:     piece,
+		From:      squareName(m.from),
+		To:        squareName(m.to),
+		Piece:     pos.board[m.from].kind.String(),
 		Player:    expectedPlayer,
-		Timestamp: time.Now(),
-		Notation:  notation,
+		Timestamp: now,
+		Notation:  san,
+		SAN:       san,
+		UCI:       pos.uci(m),
+		Promotion: m.promotion.String(),
+		Capture:   m.flags&flagCapture != 0,
+		Castle:    m.flags&flagCastle != 0,
+		EnPassant: m.flags&flagEnPassant != 0,
+		Check:     next.inCheck(),
+		Checkmate: next.inCheck() && len(replies) == 0,
+	}
+
+	// Deduct the thinking time from the mover's clock
+	if g.IsTimed() {
+		move.ClockMs = g.chargeClock(expectedPlayer, move.Timestamp).Milliseconds()
 	}
 
-	// Add move to game
+	// Add move to game and record the resulting position
+	move.PositionHash = hashKey(next.hash())
 	g.Moves = append(g.Moves, move)
+	g.MaxPly = max(g.MaxPly, len(g.Moves))
+	g.Board = next.FEN()
+
+	// Moving instead of answering declines the opponent's draw offer. Any move
+	// cancels a takeback request, which was about the previous position.
+	if g.DrawOffer != nil && g.DrawOffer.By != expectedPlayer {
+		g.DrawOffer = nil
+	}
+	g.TakebackRequest = nil
 
 	// Switch turns
 	if g.CurrentTurn == "white" {
@@ -133,7 +430,140 @@ func (g *Game) MakeMove(playerID primitive.ObjectID, from, to, piece, notation s
 		g.CurrentTurn = "white"
 	}
 
-	g.UpdatedAt = time.Now()
+	g.UpdatedAt = now
+
+	// The game is over when the opponent has no legal reply
+	if len(replies) == 0 {
+		if move.Checkmate {
+			g.finish(winnerResult(expectedPlayer), TerminationCheckmate)
+		} else {
+			g.finish(GameResultDraw, TerminationStalemate)
+		}
+		return
+	}
+
+	// Automatic draws that need no claim from either player
+	switch {
+	case g.repetitionCount(move.PositionHash) >= fivefoldRepetition:
+		g.finish(GameResultDraw, TerminationFivefoldRepetition)
+	case next.halfmoveClock >= seventyFiveMoveRulePlies:
+		g.finish(GameResultDraw, TerminationSeventyFiveMoveRule)
+	case next.insufficientMaterial():
+		g.finish(GameResultDraw, TerminationInsufficientMaterial)
+	}
+}
+
+// ClaimDraw lets a player claim a draw by threefold repetition or the fifty-move rule
+func (g *Game) ClaimDraw(playerID primitive.ObjectID) error {
+	if g.Status != GameStatusActive {
+		return errors.New("game is not active")
+	}
+	if !g.IsPlayerInGame(playerID) {
+		return errors.New("player is not part of this game")
+	}
+
+	pos, err := g.position()
+	if err != nil {
+		return err
+	}
+
+	if g.repetitionCount(hashKey(pos.hash())) >= threefoldRepetition {
+		g.finish(GameResultDraw, TerminationThreefoldRepetition)
+		return nil
+	}
+	if pos.halfmoveClock >= fiftyMoveRulePlies {
+		g.finish(GameResultDraw, TerminationFiftyMoveRule)
+		return nil
+	}
+	return errors.New("no draw claim is available in this position")
+}
+
+// LegalMoves returns the legal moves for the side to move, optionally limited to
+// moves starting on the given square. Games that are not active have no legal moves.
+func (g *Game) LegalMoves(from string) ([]LegalMove, error) {
+	legal := []LegalMove{}
+	if g.Status != GameStatusActive {
+		return legal, nil
+	}
+
+	fromSq := noSquare
+	if from != "" {
+		sq, err := parseSquare(from)
+		if err != nil {
+			return nil, err
+		}
+		fromSq = sq
+	}
+
+	pos, err := g.position()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range pos.legalMoves() {
+		if fromSq != noSquare && m.from != fromSq {
+			continue
+		}
+		legal = append(legal, LegalMove{
+			From:      squareName(m.from),
+			To:        squareName(m.to),
+			Promotion: m.promotion.String(),
+			UCI:       pos.uci(m),
+			SAN:       pos.san(m),
+		})
+	}
+	return legal, nil
+}
+
+// repetitionCount returns how many times the position with the given hash has
+// occurred in the game, including the starting position
+func (g *Game) repetitionCount(positionHash string) int {
+	count := 0
+	if start, err := ParseFEN(StartingFEN); err == nil && hashKey(start.hash()) == positionHash {
+		count++
+	}
+	for _, mv := range g.Moves {
+		if mv.PositionHash == positionHash {
+			count++
+		}
+	}
+	return count
+}
+
+// position parses the current board position
+func (g *Game) position() (*Position, error) {
+	pos, err := ParseFEN(g.Board)
+	if err != nil {
+		return nil, fmt.Errorf("corrupt game board: %w", err)
+	}
+	return pos, nil
+}
+
+// replay plays the recorded moves again from the starting position, calling visit
+// with the position after each move
+func (g *Game) replay(visit func(ply int, pos *Position)) error {
+	return replayMoves(g.Moves, visit)
+}
+
+// replayMoves plays moves from the starting position, calling visit with the
+// position after each move
+func replayMoves(moves []Move, visit func(ply int, pos *Position)) error {
+	pos, err := ParseFEN(StartingFEN)
+	if err != nil {
+		return err
+	}
+	for i, recorded := range moves {
+		promotion, err := parsePromotion(recorded.Promotion)
+		if err != nil {
+			return fmt.Errorf("failed to replay move %d: %w", i+1, err)
+		}
+		m, err := pos.findMove(recorded.From, recorded.To, promotion)
+		if err != nil {
+			return fmt.Errorf("failed to replay move %d: %w", i+1, err)
+		}
+		pos = pos.play(m)
+		visit(i+1, pos)
+	}
 	return nil
 }
 
@@ -152,26 +582,176 @@ func (g *Game) ResignGame(playerID primitive.ObjectID) error {
 		return errors.New("player is not part of this game")
 	}
 
-	g.Status = GameStatusFinished
-	g.Result = result
-	now := time.Now()
+	g.finish(result, TerminationResignation)
+	return nil
+}
+
+// AbortGame calls the game off without a result. It is only possible until both
+// players have made their first move.
+func (g *Game) AbortGame(playerID primitive.ObjectID) error {
+	if g.Status != GameStatusActive {
+		return errors.New("game is not active")
+	}
+	if !g.IsPlayerInGame(playerID) {
+		return errors.New("player is not part of this game")
+	}
+	if g.bothPlayersMoved() {
+		return errors.New("game can no longer be aborted once both players have moved")
+	}
+
+	g.abort(time.Now())
+	return nil
+}
+
+// AbortIfUnplayed aborts the game when the side to move has not made their first
+// move within the window since the game started or the opponent's first move.
+// Correspondence games are left alone. It reports whether the game was aborted.
+func (g *Game) AbortIfUnplayed(now time.Time, window time.Duration) bool {
+	if g.Status != GameStatusActive || g.IsCorrespondence() || g.bothPlayersMoved() {
+		return false
+	}
+
+	waitingSince := g.UpdatedAt
+	if len(g.Moves) > 0 {
+		waitingSince = g.Moves[len(g.Moves)-1].Timestamp
+	} else if g.StartedAt != nil {
+		waitingSince = *g.StartedAt
+	}
+	if now.Sub(waitingSince) < window {
+		return false
+	}
+
+	g.abort(now)
+	return true
+}
+
+// bothPlayersMoved reports whether both players have made their first move at some
+// point, even if the moves were taken back since. Games saved before the highest ply
+// was recorded only have their moves to go by.
+func (g *Game) bothPlayersMoved() bool {
+	return max(g.MaxPly, len(g.Moves)) >= abortablePlies
+}
+
+// ClaimAbandonment ends the game when the opponent has been gone for longer than the
+// grace period, as a win for the player or a draw as they choose. Timed games scale
+// the grace period with their base time, a tenth of it but at least a minute and at
+// most the given grace. The opponent counts as present when last seen on the game or
+// when they last moved. Correspondence games end by their move deadline instead.
+func (g *Game) ClaimAbandonment(playerID primitive.ObjectID, claim AbandonmentClaim, opponentLastSeen time.Time, grace time.Duration, now time.Time) error {
+	if g.Status != GameStatusActive {
+		return errors.New("game is not active")
+	}
+	color, err := g.GetPlayerColor(playerID)
+	if err != nil {
+		return err
+	}
+	if g.IsCorrespondence() {
+		return errors.New("correspondence games cannot be claimed as abandoned")
+	}
+
+	var result GameResult
+	switch claim {
+	case ClaimVictory:
+		result = winnerResult(color)
+	case ClaimDraw:
+		result = GameResultDraw
+	default:
+		return errors.New("claim must be win or draw")
+	}
+
+	// The opponent was around at least when the game started and when they last moved
+	lastSeen := opponentLastSeen
+	if g.StartedAt != nil && g.StartedAt.After(lastSeen) {
+		lastSeen = *g.StartedAt
+	}
+	for i := len(g.Moves) - 1; i >= 0; i-- {
+		if g.Moves[i].Player != color {
+			if g.Moves[i].Timestamp.After(lastSeen) {
+				lastSeen = g.Moves[i].Timestamp
+			}
+			break
+		}
+	}
+	if g.IsTimed() {
+		grace = min(grace, max(minAbandonmentGrace, g.TimeControl.base()/10))
+	}
+	if gone := now.Sub(lastSeen); gone < grace {
+		return fmt.Errorf("your opponent has only been gone for %s, you can claim after %s", gone.Round(time.Second), grace.Round(time.Second))
+	}
+
+	g.finish(result, TerminationAbandonment)
+	g.Status = GameStatusAbandoned
+	return nil
+}
+
+// abort ends the game without a result
+func (g *Game) abort(now time.Time) {
+	g.Status = GameStatusAborted
+	g.DrawOffer = nil
+	g.TakebackRequest = nil
 	g.FinishedAt = &now
 	g.UpdatedAt = now
+}
+
+// CheckCancel verifies that a player may cancel the game: only its creator can,
+// and only while it is still waiting for an opponent
+func (g *Game) CheckCancel(playerID primitive.ObjectID) error {
+	if g.Status != GameStatusWaiting {
+		return errors.New("only games waiting for an opponent can be cancelled")
+	}
+	creator := g.CreatedBy
+	if creator.IsZero() {
+		// Games created before the creator was recorded have only the creator seated
+		creator = g.WhitePlayer
+	}
+	if creator != playerID {
+		return errors.New("only the creator can cancel this game")
+	}
 	return nil
 }
 
-// FinishGame marks the game as finished with a result
-func (g *Game) FinishGame(result GameResult) error {
+// ExpireIfStale abandons the game when it has waited for an opponent for longer
+// than the ttl. It reports whether the game was abandoned.
+func (g *Game) ExpireIfStale(now time.Time, ttl time.Duration) bool {
+	if g.Status != GameStatusWaiting || now.Sub(g.CreatedAt) < ttl {
+		return false
+	}
+
+	g.Status = GameStatusAbandoned
+	g.Result = GameResultAbandoned
+	g.FinishedAt = &now
+	g.UpdatedAt = now
+	return true
+}
+
+// FinishGame marks the game as finished with a result and the reason it ended
+func (g *Game) FinishGame(result GameResult, termination Termination) error {
 	if g.Status != GameStatusActive {
 		return errors.New("game is not active")
 	}
 
+	g.finish(result, termination)
+	return nil
+}
+
+// finish records the end of the game
+func (g *Game) finish(result GameResult, termination Termination) {
 	g.Status = GameStatusFinished
 	g.Result = result
+	g.Termination = termination
+	g.DrawOffer = nil
+	g.TakebackRequest = nil
 	now := time.Now()
 	g.FinishedAt = &now
 	g.UpdatedAt = now
-	return nil
+}
+
+// winnerResult returns the result of a game won by the given color
+func winnerResult(color string) GameResult {
+	if color == "white" {
+		return GameResultWhiteWins
+	}
+	return GameResultBlackWins
 }
 
 // IsPlayerInGame checks if a player is part of this game
@@ -179,6 +759,25 @@ func (g *Game) IsPlayerInGame(playerID primitive.ObjectID) bool {
 	return g.WhitePlayer == playerID || g.BlackPlayer == playerID
 }
 
+// OpponentOf returns the ID of the player's opponent, which is empty while the
+// game is waiting for them
+func (g *Game) OpponentOf(playerID primitive.ObjectID) (primitive.ObjectID, error) {
+	color, err := g.GetPlayerColor(playerID)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	if color == "white" {
+		return g.BlackPlayer, nil
+	}
+	return g.WhitePlayer, nil
+}
+
+// CanView reports whether a user may watch the game: players always can, anyone
+// else only when the game allows spectators
+func (g *Game) CanView(userID primitive.ObjectID) bool {
+	return g.IsPlayerInGame(userID) || g.AllowSpectators
+}
+
 // GetPlayerColor returns the color of the player in this game
 func (g *Game) GetPlayerColor(playerID primitive.ObjectID) (string, error) {
 	if g.WhitePlayer == playerID {
@@ -192,14 +791,23 @@ func (g *Game) GetPlayerColor(playerID primitive.ObjectID) (string, error) {
 
 // IsValid checks if the game entity is valid
 func (g *Game) IsValid() error {
-	if g.WhitePlayer.IsZero() {
-		return errors.New("white player cannot be empty")
+	if g.WhitePlayer.IsZero() && g.BlackPlayer.IsZero() {
+		return errors.New("game must have at least one player")
 	}
-	if g.Status == GameStatusActive && g.BlackPlayer.IsZero() {
-		return errors.New("active game must have black player")
+	if g.Status != GameStatusWaiting && (g.WhitePlayer.IsZero() || g.BlackPlayer.IsZero()) {
+		return errors.New("started game must have both players")
 	}
 	if g.CurrentTurn != "white" && g.CurrentTurn != "black" {
 		return errors.New("current turn must be white or black")
 	}
 	return nil
+}
+
+// generateInviteCode generates an unguessable code for joining a private game
+func generateInviteCode() (string, error) {
+	bytes := make([]byte, 16) // 128 bits
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
 }
\ No newline at end of file
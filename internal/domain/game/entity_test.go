@@ -0,0 +1,68 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newAbandonedGame starts a game and plays the given moves, after which the opponent
+// of the claiming player disappears
+func newAbandonedGame(t *testing.T, baseMinutes int, moves ...string) (*Game, primitive.ObjectID, primitive.ObjectID) {
+	t.Helper()
+	white, black := primitive.NewObjectID(), primitive.NewObjectID()
+	g, err := NewGameBetween(white, black, GameOptions{TimeControl: &TimeControl{BaseMinutes: baseMinutes}})
+	if err != nil {
+		t.Fatalf("NewGameBetween: %v", err)
+	}
+	for _, notation := range moves {
+		player := white
+		if g.CurrentTurn == "black" {
+			player = black
+		}
+		if err := g.MakeMoveNotation(player, notation); err != nil {
+			t.Fatalf("move %s: %v", notation, err)
+		}
+	}
+	return g, white, black
+}
+
+func TestClaimAbandonment(t *testing.T) {
+	const grace = 5 * time.Minute
+
+	cases := []struct {
+		name        string
+		baseMinutes int
+		moves       []string
+		gone        time.Duration
+		want        bool
+	}{
+		// Black claims after white's last move; 10 minute games have a one minute grace
+		{"opponent to move, too early", 10, []string{"e4", "e5"}, 30 * time.Second, false},
+		{"opponent to move", 10, []string{"e4", "e5"}, 2 * time.Minute, true},
+		{"claimer to move, too early", 10, []string{"e4"}, 30 * time.Second, false},
+		{"claimer to move", 10, []string{"e4"}, 2 * time.Minute, true},
+
+		// A tenth of the base time, capped by the given grace
+		{"longer game, too early", 30, []string{"e4"}, 2 * time.Minute, false},
+		{"longer game", 30, []string{"e4"}, 4 * time.Minute, true},
+		{"long game, too early", 90, []string{"e4"}, 4 * time.Minute, false},
+		{"long game", 90, []string{"e4"}, 6 * time.Minute, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			g, _, black := newAbandonedGame(t, tc.baseMinutes, tc.moves...)
+			lastSeen := g.Moves[0].Timestamp // White's first move, their last sign of life
+
+			err := g.ClaimAbandonment(black, ClaimVictory, lastSeen, grace, lastSeen.Add(tc.gone))
+			if got := err == nil; got != tc.want {
+				t.Fatalf("claim accepted = %v, want %v (error: %v)", got, tc.want, err)
+			}
+			if tc.want && (g.Status != GameStatusAbandoned || g.Result != GameResultBlackWins) {
+				t.Errorf("status %s and result %s, want %s and %s", g.Status, g.Result, GameStatusAbandoned, GameResultBlackWins)
+			}
+		})
+	}
+}
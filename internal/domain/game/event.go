@@ -0,0 +1,125 @@
+package game
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EventType identifies what happened in a game
+type EventType string
+
+const (
+	EventGameState     EventType = "state"         // Snapshot sent when a client connects
+	EventGameJoined    EventType = "joined"        // Second player joined, the game started
+	EventMoveMade      EventType = "move"          // A move was played
+	EventGameResigned  EventType = "resigned"      // A player resigned
+	EventGameFinished  EventType = "finished"      // The game ended in any other way
+	EventGameAborted   EventType = "aborted"       // The game was called off before both players moved
+	EventGameCancelled EventType = "cancelled"     // The game was cancelled or expired before an opponent joined
+	EventDrawOffered   EventType = "draw_offered"  // A player offered a draw
+	EventDrawDeclined  EventType = "draw_declined" // The draw offer was declined
+
+	EventTakebackRequested EventType = "takeback_requested" // A player asked to take back their last move
+	EventTakebackDeclined  EventType = "takeback_declined"  // The takeback request was declined
+	EventTakeback          EventType = "takeback"           // Moves were taken back, Ply is the new number of moves
+
+	// Events addressed to a single user, about a game they are not following yet
+	EventMatched           EventType = "matched"            // Matchmaking paired the user's seek into the game
+	EventChallengeAccepted EventType = "challenge_accepted" // The opponent accepted the user's challenge, the game started
+)
+
+// Event is a real-time notification about a game, carrying enough state for a client
+// to update its board and clocks without fetching the game again
+type Event struct {
+	Type          EventType               `json:"type"`
+	GameID        primitive.ObjectID      `json:"game_id"`
+	UserID        *primitive.ObjectID     `json:"user_id,omitempty"` // Set on events sent to one user instead of the game's followers
+	Ply           int                     `json:"ply"`               // Number of moves played so far
+	Move          *Move                   `json:"move,omitempty"`
+	Board         string                  `json:"board"`
+	CurrentTurn   string                  `json:"current_turn"`
+	Status        GameStatus              `json:"status"`
+	Result        GameResult              `json:"result,omitempty"`
+	Termination   Termination             `json:"termination,omitempty"`
+	DrawOffer     *DrawOffer              `json:"draw_offer,omitempty"`       // Pending draw offer, gone once answered or cancelled by a move
+	Takeback      *TakebackRequest        `json:"takeback_request,omitempty"` // Pending takeback request
+	RatingChanges map[string]RatingChange `json:"rating_changes,omitempty"`
+	Clock         *Clock                  `json:"clock,omitempty"`
+	Deadline      *time.Time              `json:"deadline,omitempty"` // When the side to move runs out of time
+	Timestamp     time.Time               `json:"timestamp"`
+}
+
+// NewEvent creates an event describing the current state of the game. Move events
+// carry the last move played.
+func NewEvent(eventType EventType, g *Game) Event {
+	event := Event{
+		Type:          eventType,
+		GameID:        g.ID,
+		Ply:           len(g.Moves),
+		Board:         g.Board,
+		CurrentTurn:   g.CurrentTurn,
+		Status:        g.Status,
+		Result:        g.Result,
+		Termination:   g.Termination,
+		DrawOffer:     g.DrawOffer,
+		Takeback:      g.TakebackRequest,
+		RatingChanges: g.RatingChanges,
+		Clock:         g.Clock,
+		Deadline:      g.MoveDeadline(),
+		Timestamp:     time.Now(),
+	}
+	if eventType == EventMoveMade && len(g.Moves) > 0 {
+		last := g.Moves[len(g.Moves)-1]
+		event.Move = &last
+	}
+	return event
+}
+
+// NewUserEvent creates an event about a game that is sent to a single user
+func NewUserEvent(eventType EventType, g *Game, userID primitive.ObjectID) Event {
+	event := NewEvent(eventType, g)
+	event.UserID = &userID
+	return event
+}
+
+// MoveEvents returns the events caused by the last move: the move itself and, when
+// the move ended the game, a finished event
+func MoveEvents(g *Game) []Event {
+	events := []Event{NewEvent(EventMoveMade, g)}
+	if g.Status == GameStatusFinished {
+		events = append(events, NewEvent(EventGameFinished, g))
+	}
+	return events
+}
+
+// MoveEventsSince rebuilds the move events after the given ply from the move history,
+// for clients that reconnect after missing some events. The last move carries the
+// current state of the game.
+func (g *Game) MoveEventsSince(ply int) ([]Event, error) {
+	var events []Event
+	err := g.replay(func(n int, pos *Position) {
+		if n <= ply {
+			return
+		}
+		if n == len(g.Moves) {
+			events = append(events, NewEvent(EventMoveMade, g))
+			return
+		}
+		recorded := g.Moves[n-1]
+		events = append(events, Event{
+			Type:        EventMoveMade,
+			GameID:      g.ID,
+			Ply:         n,
+			Move:        &recorded,
+			Board:       pos.FEN(),
+			CurrentTurn: pos.turn.String(),
+			Status:      GameStatusActive,
+			Timestamp:   recorded.Timestamp,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -0,0 +1,423 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrIllegalMove is matched by every error returned for a move that breaks the rules of chess
+var ErrIllegalMove = errors.New("illegal move")
+
+// IllegalMoveError describes why a requested move was rejected
+type IllegalMoveError struct {
+	Move   string // The move as requested, e.g. "e2e5"
+	Reason string
+}
+
+// Error implements the error interface
+func (e *IllegalMoveError) Error() string {
+	return fmt.Sprintf("illegal move %s: %s", e.Move, e.Reason)
+}
+
+// Is makes errors.Is(err, ErrIllegalMove) match any IllegalMoveError
+func (e *IllegalMoveError) Is(target error) bool {
+	return target == ErrIllegalMove
+}
+
+// moveFlags carries properties of a move that are needed to play it
+type moveFlags uint8
+
+const (
+	flagCapture moveFlags = 1 << iota
+	flagEnPassant
+	flagCastle
+	flagDoublePush
+)
+
+// move is a fully specified move in a Position
+type move struct {
+	from      int
+	to        int
+	promotion pieceKind
+	flags     moveFlags
+}
+
+var (
+	knightSteps  = [8][2]int{{1, 2}, {2, 1}, {2, -1}, {1, -2}, {-1, -2}, {-2, -1}, {-2, 1}, {-1, 2}}
+	kingSteps    = [8][2]int{{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1}}
+	bishopDirs   = [4][2]int{{1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
+	rookDirs     = [4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+	promotionSet = [4]pieceKind{queen, rook, bishop, knight}
+)
+
+// castlingLoss holds the castling rights lost when a piece moves from or to each square
+var castlingLoss = func() [64]castlingRights {
+	var loss [64]castlingRights
+	loss[squareOf(0, 0)] = whiteQueenside
+	loss[squareOf(7, 0)] = whiteKingside
+	loss[squareOf(4, 0)] = whiteKingside | whiteQueenside
+	loss[squareOf(0, 7)] = blackQueenside
+	loss[squareOf(7, 7)] = blackKingside
+	loss[squareOf(4, 7)] = blackKingside | blackQueenside
+	return loss
+}()
+
+// pawnDirection returns the rank step of a side's pawns
+func pawnDirection(c color) int {
+	if c == white {
+		return 1
+	}
+	return -1
+}
+
+// isAttacked reports whether any piece of side "by" attacks the square
+func (p *Position) isAttacked(sq int, by color) bool {
+	file, rank := fileOf(sq), rankOf(sq)
+
+	// Pawns attack diagonally forward, so look one rank behind the target
+	pawnRank := rank - pawnDirection(by)
+	for _, df := range [2]int{-1, 1} {
+		if onBoard(file+df, pawnRank) {
+			pc := p.board[squareOf(file+df, pawnRank)]
+			if pc.kind == pawn && pc.color == by {
+				return true
+			}
+		}
+	}
+
+	for _, step := range knightSteps {
+		f, r := file+step[0], rank+step[1]
+		if onBoard(f, r) {
+			pc := p.board[squareOf(f, r)]
+			if pc.kind == knight && pc.color == by {
+				return true
+			}
+		}
+	}
+
+	for _, step := range kingSteps {
+		f, r := file+step[0], rank+step[1]
+		if onBoard(f, r) {
+			pc := p.board[squareOf(f, r)]
+			if pc.kind == king && pc.color == by {
+				return true
+			}
+		}
+	}
+
+	if p.slidingAttack(file, rank, by, bishopDirs, bishop) || p.slidingAttack(file, rank, by, rookDirs, rook) {
+		return true
+	}
+
+	return false
+}
+
+// slidingAttack looks along each direction for the first piece and reports
+// whether it is an enemy slider (or queen) moving along that line
+func (p *Position) slidingAttack(file, rank int, by color, dirs [4][2]int, slider pieceKind) bool {
+	for _, dir := range dirs {
+		f, r := file+dir[0], rank+dir[1]
+		for onBoard(f, r) {
+			pc := p.board[squareOf(f, r)]
+			if !pc.isEmpty() {
+				if pc.color == by && (pc.kind == slider || pc.kind == queen) {
+					return true
+				}
+				break
+			}
+			f, r = f+dir[0], r+dir[1]
+		}
+	}
+	return false
+}
+
+// inCheck reports whether the side to move is in check
+func (p *Position) inCheck() bool {
+	return p.kingAttacked(p.turn)
+}
+
+// kingAttacked reports whether the given side's king is attacked
+func (p *Position) kingAttacked(c color) bool {
+	ks := p.kingSquare(c)
+	return ks != noSquare && p.isAttacked(ks, c.opponent())
+}
+
+// pseudoLegalMoves generates all moves for the side to move without
+// checking whether they leave the king in check
+func (p *Position) pseudoLegalMoves() []move {
+	moves := make([]move, 0, 48)
+	us := p.turn
+
+	for sq, pc := range p.board {
+		if pc.isEmpty() || pc.color != us {
+			continue
+		}
+		file, rank := fileOf(sq), rankOf(sq)
+
+		switch pc.kind {
+		case pawn:
+			moves = p.appendPawnMoves(moves, sq)
+		case knight:
+			moves = p.appendStepMoves(moves, sq, knightSteps)
+		case king:
+			moves = p.appendStepMoves(moves, sq, kingSteps)
+			moves = p.appendCastlingMoves(moves, sq)
+		case bishop:
+			moves = p.appendSlidingMoves(moves, file, rank, bishopDirs)
+		case rook:
+			moves = p.appendSlidingMoves(moves, file, rank, rookDirs)
+		case queen:
+			moves = p.appendSlidingMoves(moves, file, rank, bishopDirs)
+			moves = p.appendSlidingMoves(moves, file, rank, rookDirs)
+		}
+	}
+
+	return moves
+}
+
+// appendPawnMoves adds pushes, captures, en passant and promotions for the pawn on sq
+func (p *Position) appendPawnMoves(moves []move, sq int) []move {
+	us := p.turn
+	dir := pawnDirection(us)
+	file, rank := fileOf(sq), rankOf(sq)
+	startRank, lastRank := 1, 7
+	if us == black {
+		startRank, lastRank = 6, 0
+	}
+
+	add := func(to int, flags moveFlags) {
+		if rankOf(to) == lastRank {
+			for _, kind := range promotionSet {
+				moves = append(moves, move{from: sq, to: to, promotion: kind, flags: flags})
+			}
+			return
+		}
+		moves = append(moves, move{from: sq, to: to, flags: flags})
+	}
+
+	// Pushes
+	if onBoard(file, rank+dir) && p.board[squareOf(file, rank+dir)].isEmpty() {
+		add(squareOf(file, rank+dir), 0)
+		if rank == startRank && p.board[squareOf(file, rank+2*dir)].isEmpty() {
+			add(squareOf(file, rank+2*dir), flagDoublePush)
+		}
+	}
+
+	// Captures
+	for _, df := range [2]int{-1, 1} {
+		f, r := file+df, rank+dir
+		if !onBoard(f, r) {
+			continue
+		}
+		to := squareOf(f, r)
+		target := p.board[to]
+		if !target.isEmpty() && target.color != us {
+			add(to, flagCapture)
+		} else if to == p.epSquare {
+			add(to, flagCapture|flagEnPassant)
+		}
+	}
+
+	return moves
+}
+
+// appendStepMoves adds single-step moves (knight or king) from sq
+func (p *Position) appendStepMoves(moves []move, sq int, steps [8][2]int) []move {
+	file, rank := fileOf(sq), rankOf(sq)
+	for _, step := range steps {
+		f, r := file+step[0], rank+step[1]
+		if !onBoard(f, r) {
+			continue
+		}
+		to := squareOf(f, r)
+		target := p.board[to]
+		if target.isEmpty() {
+			moves = append(moves, move{from: sq, to: to})
+		} else if target.color != p.turn {
+			moves = append(moves, move{from: sq, to: to, flags: flagCapture})
+		}
+	}
+	return moves
+}
+
+// appendSlidingMoves adds moves along the given directions until blocked
+func (p *Position) appendSlidingMoves(moves []move, file, rank int, dirs [4][2]int) []move {
+	from := squareOf(file, rank)
+	for _, dir := range dirs {
+		f, r := file+dir[0], rank+dir[1]
+		for onBoard(f, r) {
+			to := squareOf(f, r)
+			target := p.board[to]
+			if target.isEmpty() {
+				moves = append(moves, move{from: from, to: to})
+			} else {
+				if target.color != p.turn {
+					moves = append(moves, move{from: from, to: to, flags: flagCapture})
+				}
+				break
+			}
+			f, r = f+dir[0], r+dir[1]
+		}
+	}
+	return moves
+}
+
+// appendCastlingMoves adds castling moves for the king on sq when the rights,
+// empty squares and safety conditions all hold
+func (p *Position) appendCastlingMoves(moves []move, sq int) []move {
+	us := p.turn
+	backRank := 0
+	kingside, queenside := whiteKingside, whiteQueenside
+	if us == black {
+		backRank = 7
+		kingside, queenside = blackKingside, blackQueenside
+	}
+	if sq != squareOf(4, backRank) {
+		return moves
+	}
+	them := us.opponent()
+	empty := func(file int) bool { return p.board[squareOf(file, backRank)].isEmpty() }
+	safe := func(file int) bool { return !p.isAttacked(squareOf(file, backRank), them) }
+	rookOn := func(file int) bool {
+		pc := p.board[squareOf(file, backRank)]
+		return pc.kind == rook && pc.color == us
+	}
+
+	if p.castling&(kingside|queenside) == 0 || !safe(4) {
+		return moves
+	}
+	if p.castling&kingside != 0 && rookOn(7) && empty(5) && empty(6) && safe(5) && safe(6) {
+		moves = append(moves, move{from: sq, to: squareOf(6, backRank), flags: flagCastle})
+	}
+	if p.castling&queenside != 0 && rookOn(0) && empty(1) && empty(2) && empty(3) && safe(2) && safe(3) {
+		moves = append(moves, move{from: sq, to: squareOf(2, backRank), flags: flagCastle})
+	}
+	return moves
+}
+
+// legalMoves generates all legal moves for the side to move
+func (p *Position) legalMoves() []move {
+	pseudo := p.pseudoLegalMoves()
+	legal := pseudo[:0]
+	for _, m := range pseudo {
+		next := p.play(m)
+		if !next.kingAttacked(p.turn) {
+			legal = append(legal, m)
+		}
+	}
+	return legal
+}
+
+// play returns the position after making the move; the move must come from the generator
+func (p *Position) play(m move) *Position {
+	next := *p
+	pc := next.board[m.from]
+	next.board[m.from] = piece{}
+
+	// The halfmove clock counts moves since the last capture or pawn move
+	if pc.kind == pawn || m.flags&flagCapture != 0 {
+		next.halfmoveClock = 0
+	} else {
+		next.halfmoveClock++
+	}
+	if p.turn == black {
+		next.fullmoveNumber++
+	}
+
+	if m.flags&flagEnPassant != 0 {
+		next.board[squareOf(fileOf(m.to), rankOf(m.from))] = piece{}
+	}
+	if m.promotion != noPiece {
+		pc.kind = m.promotion
+	}
+	next.board[m.to] = pc
+
+	if m.flags&flagCastle != 0 {
+		rank := rankOf(m.from)
+		rookFrom, rookTo := squareOf(7, rank), squareOf(5, rank)
+		if fileOf(m.to) == 2 {
+			rookFrom, rookTo = squareOf(0, rank), squareOf(3, rank)
+		}
+		next.board[rookTo] = next.board[rookFrom]
+		next.board[rookFrom] = piece{}
+	}
+
+	next.castling &^= castlingLoss[m.from] | castlingLoss[m.to]
+
+	next.epSquare = noSquare
+	if m.flags&flagDoublePush != 0 {
+		next.epSquare = (m.from + m.to) / 2
+	}
+
+	next.turn = p.turn.opponent()
+	return &next
+}
+
+// parsePromotion parses a promotion piece given as a name ("knight") or letter ("n").
+// An empty string means no promotion was requested.
+func parsePromotion(s string) (pieceKind, error) {
+	s = strings.ToLower(s)
+	if s == "" {
+		return noPiece, nil
+	}
+	for _, kind := range promotionSet {
+		if s == kind.String() || (len(s) == 1 && s[0] == pieceLetters[kind]) {
+			return kind, nil
+		}
+	}
+	return noPiece, fmt.Errorf("cannot promote to %q", s)
+}
+
+// findMove resolves a move given as from/to squares (and an optional promotion piece)
+// against the legal moves of the position. Pawns reaching the last rank promote to a
+// queen unless another piece is requested.
+func (p *Position) findMove(from, to string, promotion pieceKind) (move, error) {
+	requested := from + to
+	if promotion != noPiece {
+		requested += string(pieceLetters[promotion])
+	}
+	illegal := func(reason string) (move, error) {
+		return move{}, &IllegalMoveError{Move: requested, Reason: reason}
+	}
+
+	fromSq, err := parseSquare(from)
+	if err != nil {
+		return illegal(err.Error())
+	}
+	toSq, err := parseSquare(to)
+	if err != nil {
+		return illegal(err.Error())
+	}
+
+	pc := p.board[fromSq]
+	if pc.isEmpty() {
+		return illegal("no piece on " + squareName(fromSq))
+	}
+	if pc.color != p.turn {
+		return illegal("the piece on " + squareName(fromSq) + " belongs to the opponent")
+	}
+
+	for _, m := range p.legalMoves() {
+		if m.from != fromSq || m.to != toSq {
+			continue
+		}
+		if m.promotion == noPiece {
+			if promotion != noPiece {
+				return illegal("only a pawn reaching the last rank can promote")
+			}
+			return m, nil
+		}
+		if m.promotion == promotion || (promotion == noPiece && m.promotion == queen) {
+			return m, nil
+		}
+	}
+
+	// Distinguish moves that are geometrically possible but expose the king
+	for _, m := range p.pseudoLegalMoves() {
+		if m.from == fromSq && m.to == toSq {
+			return illegal("move would leave the king in check")
+		}
+	}
+	return illegal(fmt.Sprintf("the %s on %s cannot move to %s", pc.kind, squareName(fromSq), squareName(toSq)))
+}
@@ -0,0 +1,170 @@
+package game
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	uciPattern = regexp.MustCompile(`^([a-h][1-8])([a-h][1-8])([qrbn])?$`)
+	sanPattern = regexp.MustCompile(`^([NBRQK])?([a-h])?([1-8])?(x)?([a-h][1-8])(?:=?([NBRQ]))?$`)
+)
+
+// uci returns the move in UCI notation, e.g. "e2e4" or "e7e8q"
+func (p *Position) uci(m move) string {
+	s := squareName(m.from) + squareName(m.to)
+	if m.promotion != noPiece {
+		s += string(pieceLetters[m.promotion])
+	}
+	return s
+}
+
+// san returns the move in Standard Algebraic Notation, e.g. "Nbd7", "exd8=Q+" or "O-O"
+func (p *Position) san(m move) string {
+	var sb strings.Builder
+	pc := p.board[m.from]
+
+	switch {
+	case m.flags&flagCastle != 0:
+		if fileOf(m.to) == 6 {
+			sb.WriteString("O-O")
+		} else {
+			sb.WriteString("O-O-O")
+		}
+	case pc.kind == pawn:
+		if m.flags&flagCapture != 0 {
+			sb.WriteByte(byte('a' + fileOf(m.from)))
+			sb.WriteByte('x')
+		}
+		sb.WriteString(squareName(m.to))
+		if m.promotion != noPiece {
+			sb.WriteByte('=')
+			sb.WriteByte(piece{kind: m.promotion}.letter())
+		}
+	default:
+		sb.WriteByte(piece{kind: pc.kind}.letter())
+		sb.WriteString(p.disambiguation(m))
+		if m.flags&flagCapture != 0 {
+			sb.WriteByte('x')
+		}
+		sb.WriteString(squareName(m.to))
+	}
+
+	next := p.play(m)
+	if next.inCheck() {
+		if len(next.legalMoves()) == 0 {
+			sb.WriteByte('#')
+		} else {
+			sb.WriteByte('+')
+		}
+	}
+	return sb.String()
+}
+
+// disambiguation returns the origin file, rank or square needed to tell the move
+// apart from other legal moves of the same piece type to the same square
+func (p *Position) disambiguation(m move) string {
+	kind := p.board[m.from].kind
+	ambiguous, sameFile, sameRank := false, false, false
+	for _, other := range p.legalMoves() {
+		if other.from == m.from || other.to != m.to || p.board[other.from].kind != kind {
+			continue
+		}
+		ambiguous = true
+		if fileOf(other.from) == fileOf(m.from) {
+			sameFile = true
+		}
+		if rankOf(other.from) == rankOf(m.from) {
+			sameRank = true
+		}
+	}
+
+	switch {
+	case !ambiguous:
+		return ""
+	case !sameFile:
+		return squareName(m.from)[:1]
+	case !sameRank:
+		return squareName(m.from)[1:]
+	default:
+		return squareName(m.from)
+	}
+}
+
+// parseMove resolves a move written in UCI ("g1f3", "e7e8q") or SAN ("Nf3", "O-O",
+// "exd8=Q+") against the legal moves of the position
+func (p *Position) parseMove(text string) (move, error) {
+	text = strings.TrimSpace(text)
+	illegal := func(reason string) (move, error) {
+		return move{}, &IllegalMoveError{Move: text, Reason: reason}
+	}
+	if text == "" {
+		return illegal("move is empty")
+	}
+
+	if parts := uciPattern.FindStringSubmatch(text); parts != nil {
+		promotion, err := parsePromotion(parts[3])
+		if err != nil {
+			return illegal(err.Error())
+		}
+		return p.findMove(parts[1], parts[2], promotion)
+	}
+
+	// Check, mate and annotation suffixes carry no information for parsing
+	san := strings.TrimRight(text, "+#!?")
+	san = strings.ReplaceAll(san, "0", "O")
+
+	if san == "O-O" || san == "O-O-O" {
+		targetFile := 6
+		if san == "O-O-O" {
+			targetFile = 2
+		}
+		for _, m := range p.legalMoves() {
+			if m.flags&flagCastle != 0 && fileOf(m.to) == targetFile {
+				return m, nil
+			}
+		}
+		return illegal("castling is not allowed in this position")
+	}
+
+	parts := sanPattern.FindStringSubmatch(san)
+	if parts == nil {
+		return illegal("not valid UCI or SAN notation")
+	}
+	kind := pawn
+	if parts[1] != "" {
+		pc, _ := pieceFromLetter(parts[1][0])
+		kind = pc.kind
+	}
+	to, _ := parseSquare(parts[5])
+	promotion := noPiece
+	if parts[6] != "" {
+		pc, _ := pieceFromLetter(parts[6][0])
+		promotion = pc.kind
+	}
+
+	var matches []move
+	for _, m := range p.legalMoves() {
+		switch {
+		case m.to != to || p.board[m.from].kind != kind || m.flags&flagCastle != 0:
+		case parts[2] != "" && fileOf(m.from) != int(parts[2][0]-'a'):
+		case parts[3] != "" && rankOf(m.from) != int(parts[3][0]-'1'):
+		case parts[4] != "" && m.flags&flagCapture == 0:
+		case m.promotion != promotion:
+		default:
+			matches = append(matches, m)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		if kind == pawn && promotion == noPiece && (rankOf(to) == 0 || rankOf(to) == 7) {
+			return illegal("promotion piece is required")
+		}
+		return illegal("no legal move matches")
+	case 1:
+		return matches[0], nil
+	default:
+		return illegal("ambiguous move, specify the origin file or rank")
+	}
+}
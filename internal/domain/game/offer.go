@@ -0,0 +1,217 @@
+package game
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Limits on draw offers so a player cannot pester the opponent with them
+const (
+	maxDrawOffers          = 3 // Per player and game
+	drawOfferCooldownPlies = 2 // The offerer must have moved again before offering another draw
+)
+
+// DrawOffer is a player's proposal to end the game in a draw
+type DrawOffer struct {
+	By        string    `bson:"by" json:"by"`   // Color of the player who offered
+	Ply       int       `bson:"ply" json:"ply"` // Number of moves played when the offer was made
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+// OfferDraw records a draw offer from a player to their opponent. The offer stays
+// open until the opponent answers it or makes a move.
+func (g *Game) OfferDraw(playerID primitive.ObjectID) error {
+	color, err := g.activePlayerColor(playerID)
+	if err != nil {
+		return err
+	}
+
+	if g.DrawOffer != nil {
+		if g.DrawOffer.By == color {
+			return errors.New("you have already offered a draw")
+		}
+		return errors.New("your opponent has offered a draw, accept or decline it instead")
+	}
+
+	// Rate limit the offers of this player
+	offers := 0
+	lastPly := -drawOfferCooldownPlies
+	for _, offer := range g.DrawOffers {
+		if offer.By == color {
+			offers++
+			lastPly = offer.Ply
+		}
+	}
+	if offers >= maxDrawOffers {
+		return errors.New("no draw offers left in this game")
+	}
+	if len(g.Moves) < lastPly+drawOfferCooldownPlies {
+		return errors.New("you must make a move before offering another draw")
+	}
+
+	now := time.Now()
+	offer := DrawOffer{
+		By:        color,
+		Ply:       len(g.Moves),
+		CreatedAt: now,
+	}
+	g.DrawOffer = &offer
+	g.DrawOffers = append(g.DrawOffers, offer)
+	g.UpdatedAt = now
+	return nil
+}
+
+// AcceptDraw ends the game as a draw by agreement when the opponent has offered one
+func (g *Game) AcceptDraw(playerID primitive.ObjectID) error {
+	if err := g.checkDrawOfferTo(playerID); err != nil {
+		return err
+	}
+
+	g.finish(GameResultDraw, TerminationAgreement)
+	return nil
+}
+
+// DeclineDraw turns down the opponent's draw offer
+func (g *Game) DeclineDraw(playerID primitive.ObjectID) error {
+	if err := g.checkDrawOfferTo(playerID); err != nil {
+		return err
+	}
+
+	g.DrawOffer = nil
+	g.UpdatedAt = time.Now()
+	return nil
+}
+
+// checkDrawOfferTo verifies that the player has a draw offer to answer
+func (g *Game) checkDrawOfferTo(playerID primitive.ObjectID) error {
+	color, err := g.activePlayerColor(playerID)
+	if err != nil {
+		return err
+	}
+	if g.DrawOffer == nil || g.DrawOffer.By == color {
+		return errors.New("there is no draw offer to answer")
+	}
+	return nil
+}
+
+// TakebackRequest is a player's request to take back their last move
+type TakebackRequest struct {
+	By        string    `bson:"by" json:"by"`   // Color of the player who asked
+	Ply       int       `bson:"ply" json:"ply"` // Number of moves played when the request was made
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+// RequestTakeback asks the opponent to let the player take back their last move.
+// The request stays open until the opponent answers it or a move is made.
+func (g *Game) RequestTakeback(playerID primitive.ObjectID) error {
+	color, err := g.activePlayerColor(playerID)
+	if err != nil {
+		return err
+	}
+	if !g.AllowTakebacks {
+		return errors.New("takebacks are not allowed in this game")
+	}
+
+	if g.TakebackRequest != nil {
+		if g.TakebackRequest.By == color {
+			return errors.New("you have already requested a takeback")
+		}
+		return errors.New("your opponent has requested a takeback, accept or decline it instead")
+	}
+	if g.takebackPlies(color) == 0 {
+		return errors.New("you have no move to take back")
+	}
+
+	now := time.Now()
+	g.TakebackRequest = &TakebackRequest{
+		By:        color,
+		Ply:       len(g.Moves),
+		CreatedAt: now,
+	}
+	g.UpdatedAt = now
+	return nil
+}
+
+// AcceptTakeback takes back the opponent's last move, and the player's own reply
+// to it if there is one, restoring the board, turn and clocks from before
+func (g *Game) AcceptTakeback(playerID primitive.ObjectID) error {
+	if err := g.checkTakebackRequestTo(playerID); err != nil {
+		return err
+	}
+
+	return g.takeBack(g.takebackPlies(g.TakebackRequest.By), time.Now())
+}
+
+// DeclineTakeback turns down the opponent's takeback request
+func (g *Game) DeclineTakeback(playerID primitive.ObjectID) error {
+	if err := g.checkTakebackRequestTo(playerID); err != nil {
+		return err
+	}
+
+	g.TakebackRequest = nil
+	g.UpdatedAt = time.Now()
+	return nil
+}
+
+// checkTakebackRequestTo verifies that the player has a takeback request to answer
+func (g *Game) checkTakebackRequestTo(playerID primitive.ObjectID) error {
+	color, err := g.activePlayerColor(playerID)
+	if err != nil {
+		return err
+	}
+	if g.TakebackRequest == nil || g.TakebackRequest.By == color {
+		return errors.New("there is no takeback request to answer")
+	}
+	return nil
+}
+
+// takebackPlies returns how many moves must be removed to take back the last move
+// of a color: one if it was the last move played, two if the opponent has replied
+// since, and zero if the color has not moved yet
+func (g *Game) takebackPlies(color string) int {
+	for plies := 1; plies <= 2 && plies <= len(g.Moves); plies++ {
+		if g.Moves[len(g.Moves)-plies].Player == color {
+			return plies
+		}
+	}
+	return 0
+}
+
+// takeBack removes the last moves and puts the game back in the state before them
+func (g *Game) takeBack(plies int, now time.Time) error {
+	kept := g.Moves[:len(g.Moves)-plies]
+
+	pos, err := ParseFEN(StartingFEN)
+	if err != nil {
+		return err
+	}
+	if err := replayMoves(kept, func(_ int, p *Position) { pos = p }); err != nil {
+		return err
+	}
+
+	g.Moves = kept
+	g.Board = pos.FEN()
+	g.CurrentTurn = pos.turn.String()
+	g.TakebackRequest = nil
+	g.DrawOffer = nil
+	// Offers made in the taken back moves still count, as if made at the current ply,
+	// so the cooldown is measured from a move that is still on the board
+	for i := range g.DrawOffers {
+		g.DrawOffers[i].Ply = min(g.DrawOffers[i].Ply, len(kept))
+	}
+	if g.IsTimed() {
+		g.rewindClock(now)
+	}
+	g.UpdatedAt = now
+	return nil
+}
+
+// activePlayerColor returns the color of a player in an active game
+func (g *Game) activePlayerColor(playerID primitive.ObjectID) (string, error) {
+	if g.Status != GameStatusActive {
+		return "", errors.New("game is not active")
+	}
+	return g.GetPlayerColor(playerID)
+}
@@ -0,0 +1,20 @@
+package game
+
+// Perft counts the leaf nodes of the legal move tree to the given depth.
+// Comparing the counts with published results verifies the move generator.
+func (p *Position) Perft(depth int) uint64 {
+	if depth <= 0 {
+		return 1
+	}
+
+	moves := p.legalMoves()
+	if depth == 1 {
+		return uint64(len(moves))
+	}
+
+	var nodes uint64
+	for _, m := range moves {
+		nodes += p.play(m).Perft(depth - 1)
+	}
+	return nodes
+}
@@ -0,0 +1,86 @@
+package game
+
+import "testing"
+
+// perftCases are the standard perft positions with their published node counts
+var perftCases = []struct {
+	name  string
+	fen   string
+	depth int
+	nodes uint64
+}{
+	{"start position", StartingFEN, 4, 197281},
+	{"kiwipete", "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", 4, 4085603},
+	{"position 3", "8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1", 5, 674624},
+	{"position 4", "r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1", 4, 422333},
+	{"position 4 mirrored", "r2q1rk1/pP1p2pp/Q4n2/bbp1p3/Np6/1B3NBn/pPPP1PPP/R3K2R b KQ - 0 1", 4, 422333},
+	{"position 5", "rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8", 4, 2103487},
+	{"position 6", "r4rk1/1pp1qppp/p1np1n2/2b1p1B1/2B1P1b1/P1NP1N2/1PP1QPPP/R4RK1 w - - 0 10", 4, 3894594},
+
+	// Edge cases around en passant, castling and promotion
+	{"illegal en passant pin", "3k4/3p4/8/K1P4r/8/8/8/8 b - - 0 1", 6, 1134888},
+	{"illegal en passant after double push", "8/8/4k3/8/2p5/8/B2P2K1/8 w - - 0 1", 6, 1015133},
+	{"en passant gives check", "8/8/1k6/2b5/2pP4/8/5K2/8 b - d3 0 1", 6, 1440467},
+	{"short castling gives check", "5k2/8/8/8/8/8/8/4K2R w K - 0 1", 6, 661072},
+	{"long castling gives check", "3k4/8/8/8/8/8/8/R3K3 w Q - 0 1", 6, 803711},
+	{"castling rights", "r3k2r/1b4bq/8/8/8/8/7B/R3K2R w KQkq - 0 1", 4, 1274206},
+	{"castling prevented", "r3k2r/8/3Q4/8/8/5q2/8/R3K2R b KQkq - 0 1", 4, 1720476},
+	{"promote out of check", "2K2r2/4P3/8/8/8/8/8/3k4 w - - 0 1", 6, 3821001},
+	{"discovered check", "8/8/1P2K3/8/2n5/1q6/8/5k2 b - - 0 1", 5, 1004658},
+	{"promote to give check", "4k3/1P6/8/8/8/8/K7/8 w - - 0 1", 6, 217342},
+	{"underpromote to give check", "8/P1k5/K7/8/8/8/8/8 w - - 0 1", 6, 92683},
+	{"self stalemate", "K1k5/8/P7/8/8/8/8/8 w - - 0 1", 6, 2217},
+	{"stalemate and checkmate", "8/k1P5/8/1K6/8/8/8/8 w - - 0 1", 7, 567584},
+	{"stalemate and checkmate 2", "8/8/2k5/5q2/5n2/8/5K2/8 b - - 0 1", 4, 23527},
+}
+
+func TestPerft(t *testing.T) {
+	for _, tc := range perftCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if testing.Short() && tc.nodes > 1000000 {
+				t.Skip("skipping large perft in short mode")
+			}
+
+			pos, err := ParseFEN(tc.fen)
+			if err != nil {
+				t.Fatalf("ParseFEN(%q) failed: %v", tc.fen, err)
+			}
+
+			if got := pos.Perft(tc.depth); got != tc.nodes {
+				t.Errorf("Perft(%d) = %d, want %d", tc.depth, got, tc.nodes)
+			}
+		})
+	}
+}
+
+func TestParseFENRoundTrip(t *testing.T) {
+	for _, tc := range perftCases {
+		pos, err := ParseFEN(tc.fen)
+		if err != nil {
+			t.Fatalf("ParseFEN(%q) failed: %v", tc.fen, err)
+		}
+		if got := pos.FEN(); got != tc.fen {
+			t.Errorf("FEN() = %q, want %q", got, tc.fen)
+		}
+	}
+}
+
+func BenchmarkPerftStartPosition(b *testing.B) {
+	pos, err := ParseFEN(StartingFEN)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < b.N; i++ {
+		pos.Perft(4)
+	}
+}
+
+func BenchmarkPerftKiwipete(b *testing.B) {
+	pos, err := ParseFEN(perftCases[1].fen)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < b.N; i++ {
+		pos.Perft(3)
+	}
+}
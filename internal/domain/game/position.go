@@ -0,0 +1,313 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// StartingFEN is the FEN of the standard chess starting position
+const StartingFEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+
+// color identifies one side of the board
+type color int8
+
+const (
+	white color = iota
+	black
+)
+
+// opponent returns the other side
+func (c color) opponent() color {
+	return c ^ 1
+}
+
+// String returns the color name used throughout the API ("white" or "black")
+func (c color) String() string {
+	if c == white {
+		return "white"
+	}
+	return "black"
+}
+
+// pieceKind identifies the type of a chess piece
+type pieceKind int8
+
+const (
+	noPiece pieceKind = iota
+	pawn
+	knight
+	bishop
+	rook
+	queen
+	king
+)
+
+var pieceNames = [...]string{"", "pawn", "knight", "bishop", "rook", "queen", "king"}
+var pieceLetters = [...]byte{0, 'p', 'n', 'b', 'r', 'q', 'k'}
+
+// String returns the lowercase piece name, e.g. "knight"
+func (k pieceKind) String() string {
+	return pieceNames[k]
+}
+
+// piece is a colored piece on a square; the zero value is an empty square
+type piece struct {
+	kind  pieceKind
+	color color
+}
+
+// isEmpty reports whether the square holds no piece
+func (p piece) isEmpty() bool {
+	return p.kind == noPiece
+}
+
+// letter returns the FEN letter of the piece (uppercase for white)
+func (p piece) letter() byte {
+	l := pieceLetters[p.kind]
+	if p.color == white {
+		l -= 'a' - 'A'
+	}
+	return l
+}
+
+// pieceFromLetter parses a FEN piece letter
+func pieceFromLetter(c byte) (piece, bool) {
+	pc := piece{color: black}
+	if c >= 'A' && c <= 'Z' {
+		pc.color = white
+		c += 'a' - 'A'
+	}
+	for kind, l := range pieceLetters {
+		if kind != int(noPiece) && l == c {
+			pc.kind = pieceKind(kind)
+			return pc, true
+		}
+	}
+	return piece{}, false
+}
+
+// castlingRights is a bit set of the castling moves still available
+type castlingRights uint8
+
+const (
+	whiteKingside castlingRights = 1 << iota
+	whiteQueenside
+	blackKingside
+	blackQueenside
+)
+
+// noSquare marks the absence of a square (e.g. no en-passant target)
+const noSquare = -1
+
+// Squares are indexed 0..63 with a1 = 0, b1 = 1, ... h8 = 63
+func squareOf(file, rank int) int {
+	return rank*8 + file
+}
+
+func fileOf(sq int) int {
+	return sq % 8
+}
+
+func rankOf(sq int) int {
+	return sq / 8
+}
+
+func onBoard(file, rank int) bool {
+	return file >= 0 && file < 8 && rank >= 0 && rank < 8
+}
+
+// squareName returns the algebraic name of a square, e.g. "e4"
+func squareName(sq int) string {
+	return string([]byte{byte('a' + fileOf(sq)), byte('1' + rankOf(sq))})
+}
+
+// parseSquare parses an algebraic square name such as "e4"
+func parseSquare(s string) (int, error) {
+	s = strings.ToLower(s)
+	if len(s) != 2 || s[0] < 'a' || s[0] > 'h' || s[1] < '1' || s[1] > '8' {
+		return noSquare, fmt.Errorf("invalid square %q", s)
+	}
+	return squareOf(int(s[0]-'a'), int(s[1]-'1')), nil
+}
+
+// Position is a chess position: piece placement plus the game state carried by FEN
+type Position struct {
+	board          [64]piece
+	turn           color
+	castling       castlingRights
+	epSquare       int
+	halfmoveClock  int
+	fullmoveNumber int
+}
+
+// ParseFEN parses a position from Forsyth-Edwards Notation.
+// The halfmove clock and fullmove number may be omitted.
+func ParseFEN(fen string) (*Position, error) {
+	fields := strings.Fields(fen)
+	if len(fields) != 4 && len(fields) != 6 {
+		return nil, errors.New("invalid FEN: expected 4 or 6 fields")
+	}
+
+	p := &Position{epSquare: noSquare, fullmoveNumber: 1}
+
+	// Piece placement, from rank 8 down to rank 1
+	ranks := strings.Split(fields[0], "/")
+	if len(ranks) != 8 {
+		return nil, errors.New("invalid FEN: expected 8 ranks")
+	}
+	kings := [2]int{}
+	for i, row := range ranks {
+		rank := 7 - i
+		file := 0
+		for j := 0; j < len(row); j++ {
+			c := row[j]
+			if c >= '1' && c <= '8' {
+				file += int(c - '0')
+				continue
+			}
+			pc, ok := pieceFromLetter(c)
+			if !ok {
+				return nil, fmt.Errorf("invalid FEN: unknown piece %q", c)
+			}
+			if file > 7 {
+				return nil, fmt.Errorf("invalid FEN: rank %d is too long", rank+1)
+			}
+			if pc.kind == pawn && (rank == 0 || rank == 7) {
+				return nil, errors.New("invalid FEN: pawn on back rank")
+			}
+			if pc.kind == king {
+				kings[pc.color]++
+			}
+			p.board[squareOf(file, rank)] = pc
+			file++
+		}
+		if file != 8 {
+			return nil, fmt.Errorf("invalid FEN: rank %d has wrong length", rank+1)
+		}
+	}
+	if kings[white] != 1 || kings[black] != 1 {
+		return nil, errors.New("invalid FEN: each side must have exactly one king")
+	}
+
+	// Side to move
+	switch fields[1] {
+	case "w":
+		p.turn = white
+	case "b":
+		p.turn = black
+	default:
+		return nil, errors.New("invalid FEN: side to move must be w or b")
+	}
+
+	// Castling availability
+	if fields[2] != "-" {
+		for _, c := range fields[2] {
+			switch c {
+			case 'K':
+				p.castling |= whiteKingside
+			case 'Q':
+				p.castling |= whiteQueenside
+			case 'k':
+				p.castling |= blackKingside
+			case 'q':
+				p.castling |= blackQueenside
+			default:
+				return nil, fmt.Errorf("invalid FEN: bad castling field %q", fields[2])
+			}
+		}
+	}
+
+	// En-passant target square
+	if fields[3] != "-" {
+		sq, err := parseSquare(fields[3])
+		if err != nil || (rankOf(sq) != 2 && rankOf(sq) != 5) {
+			return nil, fmt.Errorf("invalid FEN: bad en-passant square %q", fields[3])
+		}
+		p.epSquare = sq
+	}
+
+	// Move counters
+	if len(fields) == 6 {
+		halfmove, err := strconv.Atoi(fields[4])
+		if err != nil || halfmove < 0 {
+			return nil, fmt.Errorf("invalid FEN: bad halfmove clock %q", fields[4])
+		}
+		fullmove, err := strconv.Atoi(fields[5])
+		if err != nil || fullmove < 1 {
+			return nil, fmt.Errorf("invalid FEN: bad fullmove number %q", fields[5])
+		}
+		p.halfmoveClock = halfmove
+		p.fullmoveNumber = fullmove
+	}
+
+	if p.kingAttacked(p.turn.opponent()) {
+		return nil, errors.New("invalid FEN: the side not to move is in check")
+	}
+
+	return p, nil
+}
+
+// kingSquare returns the square of the given side's king
+func (p *Position) kingSquare(c color) int {
+	for sq, pc := range p.board {
+		if pc.kind == king && pc.color == c {
+			return sq
+		}
+	}
+	return noSquare
+}
+
+// FEN serializes the position to Forsyth-Edwards Notation
+func (p *Position) FEN() string {
+	var sb strings.Builder
+
+	for rank := 7; rank >= 0; rank-- {
+		empty := 0
+		for file := 0; file < 8; file++ {
+			pc := p.board[squareOf(file, rank)]
+			if pc.isEmpty() {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				sb.WriteByte(byte('0' + empty))
+				empty = 0
+			}
+			sb.WriteByte(pc.letter())
+		}
+		if empty > 0 {
+			sb.WriteByte(byte('0' + empty))
+		}
+		if rank > 0 {
+			sb.WriteByte('/')
+		}
+	}
+
+	if p.turn == white {
+		sb.WriteString(" w ")
+	} else {
+		sb.WriteString(" b ")
+	}
+
+	if p.castling == 0 {
+		sb.WriteByte('-')
+	} else {
+		for i, c := range "KQkq" {
+			if p.castling&(1<<i) != 0 {
+				sb.WriteRune(c)
+			}
+		}
+	}
+
+	sb.WriteByte(' ')
+	if p.epSquare == noSquare {
+		sb.WriteByte('-')
+	} else {
+		sb.WriteString(squareName(p.epSquare))
+	}
+
+	fmt.Fprintf(&sb, " %d %d", p.halfmoveClock, p.fullmoveNumber)
+	return sb.String()
+}
@@ -0,0 +1,90 @@
+package game
+
+import (
+	"errors"
+
+	"chess-backend/internal/domain/rating"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NeedsRating reports whether the game is rated, has been decided and has not had
+// its rating changes applied yet
+func (g *Game) NeedsRating() bool {
+	return g.Rated && g.IsDecided() && g.RatingChanges == nil
+}
+
+// IsDecided reports whether the game ended with a winner or a draw. Besides finished
+// games, this includes games won or drawn on a claim after the opponent abandoned them.
+func (g *Game) IsDecided() bool {
+	if g.Status != GameStatusFinished && g.Status != GameStatusAbandoned {
+		return false
+	}
+	switch g.Result {
+	case GameResultWhiteWins, GameResultBlackWins, GameResultDraw:
+		return true
+	default:
+		return false
+	}
+}
+
+// RatingCategory returns the category the game is rated in
+func (g *Game) RatingCategory() rating.Category {
+	return g.TimeControl.Category()
+}
+
+// score returns white's score in the finished game
+func (g *Game) score() (float64, error) {
+	switch g.Result {
+	case GameResultWhiteWins:
+		return 1, nil
+	case GameResultBlackWins:
+		return 0, nil
+	case GameResultDraw:
+		return 0.5, nil
+	default:
+		return 0, errors.New("game has no result to rate")
+	}
+}
+
+// RatingAfter computes a player's rating after the game from their own rating and
+// their opponent's rating before it
+func (g *Game) RatingAfter(playerID primitive.ObjectID, own, opponent rating.Rating) (rating.Rating, error) {
+	if !g.NeedsRating() {
+		return own, errors.New("game is not awaiting rating")
+	}
+	if !g.IsPlayerInGame(playerID) {
+		return own, errors.New("player is not part of this game")
+	}
+
+	score, err := g.score()
+	if err != nil {
+		return own, err
+	}
+	if playerID == g.BlackPlayer {
+		score = 1 - score
+	}
+
+	return own.Update([]rating.Result{{Opponent: opponent, Score: score}}), nil
+}
+
+// SetRatingChanges records the players' rating changes on the game. It is only called
+// once both players' new ratings are stored, so a rated game without rating changes
+// still has to be rated.
+func (g *Game) SetRatingChanges(whiteBefore, whiteAfter, blackBefore, blackAfter rating.Rating) {
+	category := g.RatingCategory()
+	g.RatingChanges = map[string]RatingChange{
+		"white": newRatingChange(category, whiteBefore, whiteAfter),
+		"black": newRatingChange(category, blackBefore, blackAfter),
+	}
+}
+
+// newRatingChange summarizes a rating change for display
+func newRatingChange(category rating.Category, before, after rating.Rating) RatingChange {
+	return RatingChange{
+		Category: category,
+		Before:   before.Rounded(),
+		After:    after.Rounded(),
+		Change:   after.Rounded() - before.Rounded(),
+	}
+}
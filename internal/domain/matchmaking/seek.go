@@ -0,0 +1,119 @@
+// Package matchmaking contains the Seek domain entity and the pairing rules.
+// This is part of the Domain layer in Hexagonal Architecture.
+// Domain entities should be pure business logic without any external dependencies.
+package matchmaking
+
+import (
+	"errors"
+	"time"
+
+	"chess-backend/internal/domain/game"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SeekStatus represents the current status of a seek
+type SeekStatus string
+
+const (
+	SeekStatusSearching SeekStatus = "searching" // Waiting in the queue for an opponent
+	SeekStatusMatched   SeekStatus = "matched"   // Paired, the game has been created
+)
+
+// UnratedRating is the rating of seeks for untimed games, which are not rated
+const UnratedRating = 1500
+
+// The rating window starts narrow and widens the longer a seek waits, so players
+// are paired with close opponents when possible and with anyone eventually
+const (
+	initialRatingWindow = 100
+	ratingWindowStep    = 50
+	ratingWindowEvery   = 5 * time.Second
+	maxRatingWindow     = 1000
+)
+
+// untimedQueue is the queue key of seeks for untimed games
+const untimedQueue = "untimed"
+
+// Seek is a player's request to be paired with an opponent for a time control
+type Seek struct {
+	PlayerID    primitive.ObjectID `json:"player_id"`
+	TimeControl *game.TimeControl  `json:"time_control,omitempty"` // nil for an untimed game
+	Rated       bool               `json:"rated"`                  // Only timed seeks can be rated
+	Rating      int                `json:"rating"`
+	Status      SeekStatus         `json:"status"`
+	GameID      primitive.ObjectID `json:"game_id,omitempty"` // Set once matched
+	CreatedAt   time.Time          `json:"created_at"`
+	MatchedAt   *time.Time         `json:"matched_at,omitempty"`
+}
+
+// NewSeek creates a new seek for a player
+func NewSeek(playerID primitive.ObjectID, tc *game.TimeControl, rated bool, rating int) (*Seek, error) {
+	if playerID.IsZero() {
+		return nil, errors.New("player ID cannot be empty")
+	}
+	if tc != nil {
+		if err := tc.Validate(); err != nil {
+			return nil, err
+		}
+	} else if rated {
+		return nil, errors.New("rated games need a time control")
+	}
+
+	return &Seek{
+		PlayerID:    playerID,
+		TimeControl: tc,
+		Rated:       rated,
+		Rating:      rating,
+		Status:      SeekStatusSearching,
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// QueueKey returns the key of the queue the seek waits in; only seeks for the
+// same time control that are both rated or both casual are paired
+func (s *Seek) QueueKey() string {
+	if s.TimeControl == nil {
+		return untimedQueue
+	}
+	if s.Rated {
+		return s.TimeControl.Key() + ":rated"
+	}
+	return s.TimeControl.Key() + ":casual"
+}
+
+// RatingWindow returns how far from its own rating the seek accepts an opponent
+func (s *Seek) RatingWindow(now time.Time) int {
+	waited := now.Sub(s.CreatedAt)
+	window := initialRatingWindow + int(waited/ratingWindowEvery)*ratingWindowStep
+	if window > maxRatingWindow {
+		return maxRatingWindow
+	}
+	return window
+}
+
+// Accepts reports whether two seeks can be paired: same queue, different players,
+// and each rating within the other's window
+func (s *Seek) Accepts(other *Seek, now time.Time) bool {
+	if s.PlayerID == other.PlayerID || s.QueueKey() != other.QueueKey() {
+		return false
+	}
+	diff := s.Rating - other.Rating
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= s.RatingWindow(now) && diff <= other.RatingWindow(now)
+}
+
+// Match marks the seek as paired into a game
+func (s *Seek) Match(gameID primitive.ObjectID) error {
+	if s.Status != SeekStatusSearching {
+		return errors.New("seek is not searching")
+	}
+
+	now := time.Now()
+	s.Status = SeekStatusMatched
+	s.GameID = gameID
+	s.MatchedAt = &now
+	return nil
+}
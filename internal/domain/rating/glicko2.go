@@ -0,0 +1,111 @@
+package rating
+
+import (
+	"math"
+	"time"
+)
+
+// Glicko-2 system constants
+const (
+	glickoScale = 173.7178 // Converts between the Glicko and Glicko-2 scales
+	tau         = 0.5      // Constrains how fast the volatility changes
+	epsilon     = 0.000001 // Convergence tolerance of the volatility iteration
+
+	minDeviation         = 45.0
+	maxDeviation         = DefaultDeviation
+	provisionalDeviation = 110.0
+)
+
+// Update returns the rating after a rating period with the given results.
+// Every game is rated as its own period, so results usually holds a single game.
+// See Glickman, "Example of the Glicko-2 system".
+func (r Rating) Update(results []Result) Rating {
+	mu := (r.Rating - DefaultRating) / glickoScale
+	phi := r.Deviation / glickoScale
+	sigma := r.Volatility
+
+	if len(results) == 0 {
+		// Only the uncertainty grows when a player does not play
+		r.Deviation = clampDeviation(math.Sqrt(phi*phi+sigma*sigma) * glickoScale)
+		return r
+	}
+
+	// Estimated variance and improvement based on the game outcomes
+	var vInv, improvement float64
+	for _, result := range results {
+		muJ := (result.Opponent.Rating - DefaultRating) / glickoScale
+		gJ := g(result.Opponent.Deviation / glickoScale)
+		e := expectedScore(mu, muJ, gJ)
+		vInv += gJ * gJ * e * (1 - e)
+		improvement += gJ * (result.Score - e)
+	}
+	v := 1 / vInv
+	delta := v * improvement
+
+	sigma = newVolatility(phi, sigma, v, delta)
+
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	phi = 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	mu += phi * phi * improvement
+
+	now := time.Now()
+	return Rating{
+		Rating:     mu*glickoScale + DefaultRating,
+		Deviation:  clampDeviation(phi * glickoScale),
+		Volatility: sigma,
+		Games:      r.Games + len(results),
+		UpdatedAt:  &now,
+	}
+}
+
+// g reduces the impact of a game by the opponent's rating deviation
+func g(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+// expectedScore returns the expected score against an opponent
+func expectedScore(mu, muJ, gJ float64) float64 {
+	return 1 / (1 + math.Exp(-gJ*(mu-muJ)))
+}
+
+// newVolatility finds the new volatility with the Illinois algorithm
+func newVolatility(phi, sigma, v, delta float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(tau*tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		B = a - k*tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > epsilon {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+
+	return math.Exp(A / 2)
+}
+
+// clampDeviation keeps the deviation within bounds, so ratings never become
+// completely fixed nor less certain than a new player's
+func clampDeviation(d float64) float64 {
+	return math.Max(minDeviation, math.Min(maxDeviation, d))
+}
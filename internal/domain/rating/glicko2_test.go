@@ -0,0 +1,54 @@
+package rating
+
+import (
+	"math"
+	"testing"
+)
+
+// TestUpdateGlickmanExample checks the worked example of Glickman's
+// "Example of the Glicko-2 system": a 1500 player beats a 1400 player and loses
+// to a 1550 and a 1700 player in one rating period.
+func TestUpdateGlickmanExample(t *testing.T) {
+	player := Rating{Rating: 1500, Deviation: 200, Volatility: 0.06}
+	results := []Result{
+		{Opponent: Rating{Rating: 1400, Deviation: 30, Volatility: DefaultVolatility}, Score: 1},
+		{Opponent: Rating{Rating: 1550, Deviation: 100, Volatility: DefaultVolatility}, Score: 0},
+		{Opponent: Rating{Rating: 1700, Deviation: 300, Volatility: DefaultVolatility}, Score: 0},
+	}
+
+	got := player.Update(results)
+
+	checks := []struct {
+		name      string
+		got, want float64
+		tolerance float64
+	}{
+		{"rating", got.Rating, 1464.06, 0.01},
+		{"deviation", got.Deviation, 151.52, 0.01},
+		{"volatility", got.Volatility, 0.05999, 0.00001},
+	}
+	for _, c := range checks {
+		if math.Abs(c.got-c.want) > c.tolerance {
+			t.Errorf("%s = %.5f, want %.5f", c.name, c.got, c.want)
+		}
+	}
+	if got.Games != len(results) {
+		t.Errorf("games = %d, want %d", got.Games, len(results))
+	}
+}
+
+// TestUpdateWithoutGames checks that only the deviation grows in a period without games
+func TestUpdateWithoutGames(t *testing.T) {
+	player := Rating{Rating: 1500, Deviation: 200, Volatility: 0.06}
+
+	got := player.Update(nil)
+
+	// sqrt(phi^2 + sigma^2) on the Glicko-2 scale, converted back
+	want := math.Sqrt(math.Pow(200/glickoScale, 2)+0.06*0.06) * glickoScale
+	if math.Abs(got.Deviation-want) > 1e-9 {
+		t.Errorf("deviation = %.5f, want %.5f", got.Deviation, want)
+	}
+	if got.Rating != player.Rating || got.Volatility != player.Volatility {
+		t.Errorf("rating or volatility changed: %+v", got)
+	}
+}
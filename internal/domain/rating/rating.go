@@ -0,0 +1,99 @@
+// Package rating contains the player rating model and the Glicko-2 rating system.
+// This is part of the Domain layer in Hexagonal Architecture.
+// Domain entities should be pure business logic without any external dependencies.
+package rating
+
+import (
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Category groups games with similar time controls; players have a separate
+// rating in each category
+type Category string
+
+const (
+	CategoryBullet         Category = "bullet"
+	CategoryBlitz          Category = "blitz"
+	CategoryRapid          Category = "rapid"
+	CategoryClassical      Category = "classical"
+	CategoryCorrespondence Category = "correspondence"
+)
+
+// IsValid reports whether the category is one of the known categories
+func (c Category) IsValid() bool {
+	switch c {
+	case CategoryBullet, CategoryBlitz, CategoryRapid, CategoryClassical, CategoryCorrespondence:
+		return true
+	default:
+		return false
+	}
+}
+
+// Starting values for players without games in a category
+const (
+	DefaultRating     = 1500.0
+	DefaultDeviation  = 350.0
+	DefaultVolatility = 0.06
+)
+
+// Rating is a player's Glicko-2 rating in one category
+type Rating struct {
+	Rating     float64    `bson:"rating" json:"rating"`
+	Deviation  float64    `bson:"deviation" json:"deviation"`
+	Volatility float64    `bson:"volatility" json:"volatility"`
+	Games      int        `bson:"games" json:"games"`
+	UpdatedAt  *time.Time `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+}
+
+// Default returns the rating of a player who has not played in a category yet
+func Default() Rating {
+	return Rating{
+		Rating:     DefaultRating,
+		Deviation:  DefaultDeviation,
+		Volatility: DefaultVolatility,
+	}
+}
+
+// Rounded returns the rating rounded to a whole number for display
+func (r Rating) Rounded() int {
+	return int(math.Round(r.Rating))
+}
+
+// Provisional reports whether the rating is still too uncertain to be meaningful
+func (r Rating) Provisional() bool {
+	return r.Deviation > provisionalDeviation
+}
+
+// Result is the outcome of one game against an opponent, scored 1 for a win,
+// 0.5 for a draw and 0 for a loss
+type Result struct {
+	Opponent Rating
+	Score    float64
+}
+
+// HistoryEntry records a rating change caused by a game
+type HistoryEntry struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	GameID    primitive.ObjectID `bson:"game_id" json:"game_id"`
+	Category  Category           `bson:"category" json:"category"`
+	Before    Rating             `bson:"before" json:"before"`
+	After     Rating             `bson:"after" json:"after"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// NewHistoryEntry creates a history entry for a player's rating change in a game
+func NewHistoryEntry(userID, gameID primitive.ObjectID, category Category, before, after Rating) *HistoryEntry {
+	return &HistoryEntry{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		GameID:    gameID,
+		Category:  category,
+		Before:    before,
+		After:     after,
+		CreatedAt: time.Now(),
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"errors"
 	"time"
 
+	"chess-backend/internal/domain/rating"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -18,8 +20,15 @@ type User struct {
 	Password  string             `bson:"password" json:"-"` // Never expose password in JSON
 	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+
+	Ratings    map[rating.Category]rating.Rating `bson:"ratings,omitempty" json:"ratings,omitempty"` // One per time control category
+	RatedGames []primitive.ObjectID              `bson:"rated_games,omitempty" json:"-"`             // Latest games applied to the ratings, newest last
 }
 
+// RatedGamesKept is how many of the latest rated games are remembered per user, so a
+// game whose rating is retried is never applied to the same rating twice
+const RatedGamesKept = 50
+
 // NewUser creates a new user with hashed password
 func NewUser(username, password string) (*User, error) {
 	if username == "" {
@@ -72,6 +81,25 @@ func (u *User) UpdatePassword(newPassword string) error {
 	return nil
 }
 
+// Rating returns the user's rating in a category, or the starting rating if the
+// user has not played a rated game in it
+func (u *User) Rating(category rating.Category) rating.Rating {
+	if r, ok := u.Ratings[category]; ok {
+		return r
+	}
+	return rating.Default()
+}
+
+// HasRatedGame reports whether a game has already been applied to the user's rating
+func (u *User) HasRatedGame(gameID primitive.ObjectID) bool {
+	for _, id := range u.RatedGames {
+		if id == gameID {
+			return true
+		}
+	}
+	return false
+}
+
 // IsValid validates the user entity
 func (u *User) IsValid() bool {
 	return u.Username != "" && u.Password != ""
@@ -0,0 +1,22 @@
+// Package events defines the interfaces for distributing domain events.
+// This is part of the Ports layer in Hexagonal Architecture.
+// Ports define contracts that adapters must implement.
+package events
+
+import (
+	"context"
+
+	"chess-backend/internal/domain/game"
+)
+
+// GameEventPublisher distributes game events to every instance of the server, so
+// clients connected to any instance see changes made through another one. Events
+// with a UserID go to that user only rather than to everyone following the game.
+type GameEventPublisher interface {
+	// Publish sends an event to the subscribers of all instances
+	Publish(ctx context.Context, event game.Event) error
+
+	// Subscribe calls handler for every event published by any instance.
+	// It blocks until ctx is cancelled.
+	Subscribe(ctx context.Context, handler func(game.Event)) error
+}
@@ -0,0 +1,32 @@
+// Package repositories defines the interfaces for data persistence.
+// This is part of the Ports layer in Hexagonal Architecture.
+// Ports define contracts that adapters must implement.
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"chess-backend/internal/domain/challenge"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ChallengeRepository defines the interface for challenge data persistence
+type ChallengeRepository interface {
+	// Save creates a new challenge in the repository
+	Save(ctx context.Context, c *challenge.Challenge) error
+
+	// FindByID retrieves a challenge by its ID
+	FindByID(ctx context.Context, id primitive.ObjectID) (*challenge.Challenge, error)
+
+	// FindPendingByUser retrieves the pending challenges a user sent or received
+	FindPendingByUser(ctx context.Context, userID primitive.ObjectID) ([]*challenge.Challenge, error)
+
+	// FindExpired retrieves the pending challenges that expired before the given time
+	FindExpired(ctx context.Context, before time.Time) ([]*challenge.Challenge, error)
+
+	// Respond stores the answer to a challenge. It fails if the stored challenge is
+	// no longer pending, so only one of two concurrent answers wins.
+	Respond(ctx context.Context, c *challenge.Challenge) error
+}
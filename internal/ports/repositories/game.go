@@ -5,12 +5,17 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"chess-backend/internal/domain/game"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ErrGameModified is returned by Update when the game was changed since it was loaded
+var ErrGameModified = errors.New("game was modified by another request, please retry")
+
 // GameRepository defines the interface for game data persistence
 type GameRepository interface {
 	// Save creates or updates a game in the repository
@@ -19,19 +24,34 @@ type GameRepository interface {
 	// FindByID retrieves a game by its ID
 	FindByID(ctx context.Context, id primitive.ObjectID) (*game.Game, error)
 
-	// Update updates an existing game in the repository
+	// Update updates an existing game in the repository. It fails with ErrGameModified
+	// when the stored game has changed since this copy was loaded.
 	Update(ctx context.Context, game *game.Game) error
 
 	// Delete removes a game from the repository
 	Delete(ctx context.Context, id primitive.ObjectID) error
 
+	// DeleteWaiting removes a game that is still waiting for an opponent. It fails with
+	// ErrGameModified when the stored game has changed since this copy was loaded.
+	DeleteWaiting(ctx context.Context, game *game.Game) error
+
 	// FindByPlayerID retrieves all games for a specific player
 	FindByPlayerID(ctx context.Context, playerID primitive.ObjectID) ([]*game.Game, error)
 
 	// FindActiveGames retrieves all active games
 	FindActiveGames(ctx context.Context) ([]*game.Game, error)
 
-	// FindWaitingGames retrieves all games waiting for players
+	// FindDueGames retrieves the active games whose side to move ran out of time by now
+	FindDueGames(ctx context.Context, now time.Time) ([]*game.Game, error)
+
+	// FindUnplayedGames retrieves the active games in which both players have not moved yet
+	FindUnplayedGames(ctx context.Context) ([]*game.Game, error)
+
+	// FindUnratedGames retrieves the decided rated games that finished before the given
+	// time and still have no rating changes
+	FindUnratedGames(ctx context.Context, finishedBefore time.Time) ([]*game.Game, error)
+
+	// FindWaitingGames retrieves all public games waiting for players
 	FindWaitingGames(ctx context.Context) ([]*game.Game, error)
 
 	// FindByStatus retrieves games by their status
@@ -0,0 +1,22 @@
+// Package repositories defines the interfaces for data persistence.
+// This is part of the Ports layer in Hexagonal Architecture.
+// Ports define contracts that adapters must implement.
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PresenceRepository tracks when the players of a game were last connected to it,
+// across all instances
+type PresenceRepository interface {
+	// Touch records that a player was connected to a game at the given time
+	Touch(ctx context.Context, gameID primitive.ObjectID, playerID primitive.ObjectID, at time.Time) error
+
+	// LastSeen returns when a player was last connected to a game, or the zero time
+	// if they were never seen
+	LastSeen(ctx context.Context, gameID primitive.ObjectID, playerID primitive.ObjectID) (time.Time, error)
+}
@@ -0,0 +1,24 @@
+// Package repositories defines the interfaces for data persistence.
+// This is part of the Ports layer in Hexagonal Architecture.
+// Ports define contracts that adapters must implement.
+package repositories
+
+import (
+	"context"
+
+	"chess-backend/internal/domain/rating"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RatingHistoryRepository defines the interface for rating history persistence
+type RatingHistoryRepository interface {
+	// Save stores a rating history entry, replacing the user's entry for the same game
+	Save(ctx context.Context, entry *rating.HistoryEntry) error
+
+	// FindByGame retrieves a user's entry for a game
+	FindByGame(ctx context.Context, userID, gameID primitive.ObjectID) (*rating.HistoryEntry, error)
+
+	// FindByUserID retrieves the most recent entries of a user in a category, newest first
+	FindByUserID(ctx context.Context, userID primitive.ObjectID, category rating.Category, limit int) ([]*rating.HistoryEntry, error)
+}
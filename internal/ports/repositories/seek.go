@@ -0,0 +1,35 @@
+// Package repositories defines the interfaces for data persistence.
+// This is part of the Ports layer in Hexagonal Architecture.
+// Ports define contracts that adapters must implement.
+package repositories
+
+import (
+	"context"
+
+	"chess-backend/internal/domain/matchmaking"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SeekRepository defines the interface for the matchmaking queue. Each player has at
+// most one seek; searching seeks also wait in the queue of their time control.
+type SeekRepository interface {
+	// Save stores a seek, adding it to its queue while it is searching
+	Save(ctx context.Context, seek *matchmaking.Seek) error
+
+	// FindByPlayerID retrieves the seek of a player
+	FindByPlayerID(ctx context.Context, playerID primitive.ObjectID) (*matchmaking.Seek, error)
+
+	// FindInQueue retrieves the searching seeks of a queue ordered by rating
+	FindInQueue(ctx context.Context, queueKey string) ([]*matchmaking.Seek, error)
+
+	// Queues returns the keys of the queues that have seeks waiting
+	Queues(ctx context.Context) ([]string, error)
+
+	// Claim atomically takes a seek out of its queue. It reports false if the seek
+	// was no longer queued, e.g. because another instance claimed it first.
+	Claim(ctx context.Context, seek *matchmaking.Seek) (bool, error)
+
+	// Delete removes the seek of a player
+	Delete(ctx context.Context, playerID primitive.ObjectID) error
+}
@@ -0,0 +1,25 @@
+// Package repositories defines the interfaces for data persistence.
+// This is part of the Ports layer in Hexagonal Architecture.
+// Ports define contracts that adapters must implement.
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SpectatorRepository tracks which users are watching a game, across all instances.
+// A user may watch over several connections, each recorded separately.
+type SpectatorRepository interface {
+	// Touch records that a connection of a user is watching a game until the given
+	// time, unless touched again
+	Touch(ctx context.Context, gameID primitive.ObjectID, userID primitive.ObjectID, connectionID string, until time.Time) error
+
+	// Remove records that a connection of a user stopped watching a game
+	Remove(ctx context.Context, gameID primitive.ObjectID, userID primitive.ObjectID, connectionID string) error
+
+	// Count returns how many users are currently watching a game over at least one connection
+	Count(ctx context.Context, gameID primitive.ObjectID) (int64, error)
+}
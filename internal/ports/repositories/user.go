@@ -5,12 +5,17 @@ package repositories
 
 import (
 	"context"
+	"errors"
 
+	"chess-backend/internal/domain/rating"
 	"chess-backend/internal/domain/user"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ErrRatingModified is returned by UpdateRating when the rating was changed since it was loaded
+var ErrRatingModified = errors.New("rating was modified by another game")
+
 // UserRepository defines the interface for user data persistence
 type UserRepository interface {
 	// Save creates a new user in the repository
@@ -25,6 +30,11 @@ type UserRepository interface {
 	// Update updates an existing user in the repository
 	Update(ctx context.Context, user *user.User) error
 
+	// UpdateRating stores a user's rating in one category after a game, without touching
+	// the others. It fails with ErrRatingModified when the stored rating is no longer
+	// the one before the game, or the game has already been applied to it.
+	UpdateRating(ctx context.Context, id primitive.ObjectID, category rating.Category, before, after rating.Rating, gameID primitive.ObjectID) error
+
 	// Delete removes a user from the repository
 	Delete(ctx context.Context, id primitive.ObjectID) error
 
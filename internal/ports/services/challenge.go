@@ -0,0 +1,60 @@
+// Package services defines the interfaces for business logic services.
+// This is part of the Ports layer in Hexagonal Architecture.
+// These interfaces define the contracts for application services.
+package services
+
+import (
+	"context"
+
+	"chess-backend/internal/domain/challenge"
+	"chess-backend/internal/domain/game"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CreateChallengeRequest represents the data needed to challenge another player
+type CreateChallengeRequest struct {
+	ChallengerID primitive.ObjectID   `json:"challenger_id"`
+	Username     string               `json:"username"`               // Player being challenged
+	TimeControl  *game.TimeControl    `json:"time_control,omitempty"` // Omit for an untimed game
+	Color        game.ColorPreference `json:"color,omitempty"`        // white, black or random (default)
+	Rated        bool                 `json:"rated"`                  // Rated games need a time control
+}
+
+// ChallengeResponse represents the response for challenge operations
+type ChallengeResponse struct {
+	Message   string               `json:"message"`
+	Challenge *challenge.Challenge `json:"challenge,omitempty"`
+	GameID    string               `json:"game_id,omitempty"` // Set once the challenge is accepted
+}
+
+// ChallengeListResponse represents a user's pending challenges
+type ChallengeListResponse struct {
+	Incoming []*challenge.Challenge `json:"incoming"`
+	Outgoing []*challenge.Challenge `json:"outgoing"`
+}
+
+// ChallengeService defines the interface for direct challenges between players
+type ChallengeService interface {
+	// CreateChallenge sends a challenge to the player with the given username
+	CreateChallenge(ctx context.Context, req CreateChallengeRequest) (*ChallengeResponse, error)
+
+	// GetChallenge retrieves a challenge the user sent or received
+	GetChallenge(ctx context.Context, challengeID primitive.ObjectID, userID primitive.ObjectID) (*ChallengeResponse, error)
+
+	// ListChallenges retrieves the pending challenges a user sent or received
+	ListChallenges(ctx context.Context, userID primitive.ObjectID) (*ChallengeListResponse, error)
+
+	// AcceptChallenge accepts a challenge and starts its game
+	AcceptChallenge(ctx context.Context, challengeID primitive.ObjectID, userID primitive.ObjectID) (*ChallengeResponse, error)
+
+	// DeclineChallenge turns down a received challenge
+	DeclineChallenge(ctx context.Context, challengeID primitive.ObjectID, userID primitive.ObjectID) (*ChallengeResponse, error)
+
+	// CancelChallenge withdraws a sent challenge
+	CancelChallenge(ctx context.Context, challengeID primitive.ObjectID, userID primitive.ObjectID) (*ChallengeResponse, error)
+
+	// ExpireChallenges marks unanswered challenges past their expiry as expired and
+	// returns how many were expired
+	ExpireChallenges(ctx context.Context) (int, error)
+}
@@ -5,44 +5,116 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"chess-backend/internal/domain/game"
+	"chess-backend/internal/domain/rating"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // CreateGameRequest represents the data needed to create a new game
 type CreateGameRequest struct {
-	PlayerID primitive.ObjectID `json:"player_id"`
+	PlayerID        primitive.ObjectID   `json:"player_id"`
+	TimeControl     *game.TimeControl    `json:"time_control,omitempty"`     // Omit for an untimed game
+	AllowSpectators *bool                `json:"allow_spectators,omitempty"` // Defaults to true
+	Rated           bool                 `json:"rated"`                      // Rated games need a time control
+	Color           game.ColorPreference `json:"color,omitempty"`            // white (default), black or random
+	Private         bool                 `json:"private"`                    // Only joinable with the invite code
+	AllowTakebacks  *bool                `json:"allow_takebacks,omitempty"`  // Defaults to true for unrated games
+}
+
+// StartGameRequest represents the data needed to start a game between two known players
+type StartGameRequest struct {
+	WhitePlayerID   primitive.ObjectID `json:"white_player_id"`
+	BlackPlayerID   primitive.ObjectID `json:"black_player_id"`
+	TimeControl     *game.TimeControl  `json:"time_control,omitempty"`     // Omit for an untimed game
+	AllowSpectators *bool              `json:"allow_spectators,omitempty"` // Defaults to true
+	Rated           bool               `json:"rated"`                      // Rated games need a time control
 }
 
+// SpectatorHeartbeat is how often a spectator's connection must call WatchGame to
+// stay counted as watching
+const SpectatorHeartbeat = 30 * time.Second
+
+// PresenceHeartbeat is how often a player's live connection calls RecordPresence.
+// A player who has not been seen for AbandonmentGracePeriod counts as gone, and their
+// opponent may claim the game. Faster time controls use a shorter grace period.
+const (
+	PresenceHeartbeat      = 15 * time.Second
+	AbandonmentGracePeriod = 5 * time.Minute
+)
+
 // JoinGameRequest represents the data needed to join a game
 type JoinGameRequest struct {
+	GameID     primitive.ObjectID `json:"game_id"`
+	PlayerID   primitive.ObjectID `json:"player_id"`
+	InviteCode string             `json:"invite_code,omitempty"` // Required for private games
+}
+
+// MakeMoveRequest represents the data needed to make a move.
+// The move is given either as Move in UCI or SAN notation, or as From/To squares.
+type MakeMoveRequest struct {
+	GameID    primitive.ObjectID `json:"game_id"`
+	PlayerID  primitive.ObjectID `json:"player_id"`
+	Move      string             `json:"move"` // UCI ("e2e4", "e7e8q") or SAN ("Nf3", "O-O", "exd8=Q+")
+	From      string             `json:"from"`
+	To        string             `json:"to"`
+	Piece     string             `json:"piece"`     // Ignored; the moving piece is read from the board
+	Promotion string             `json:"promotion"` // Promotion piece, e.g. "queen" or "q"; defaults to queen
+}
+
+// ResignGameRequest represents the data needed to resign from a game
+type ResignGameRequest struct {
 	GameID   primitive.ObjectID `json:"game_id"`
 	PlayerID primitive.ObjectID `json:"player_id"`
 }
 
-// MakeMoveRequest represents the data needed to make a move
-type MakeMoveRequest struct {
+// DrawOfferRequest represents the data needed to offer, accept or decline a draw
+type DrawOfferRequest struct {
 	GameID   primitive.ObjectID `json:"game_id"`
 	PlayerID primitive.ObjectID `json:"player_id"`
-	From     string             `json:"from"`
-	To       string             `json:"to"`
-	Piece    string             `json:"piece"`
-	Notation string             `json:"notation"`
 }
 
-// ResignGameRequest represents the data needed to resign from a game
-type ResignGameRequest struct {
+// TakebackRequest represents the data needed to request, accept or decline a takeback
+type TakebackRequest struct {
+	GameID   primitive.ObjectID `json:"game_id"`
+	PlayerID primitive.ObjectID `json:"player_id"`
+}
+
+// AbortGameRequest represents the data needed to abort a game
+type AbortGameRequest struct {
+	GameID   primitive.ObjectID `json:"game_id"`
+	PlayerID primitive.ObjectID `json:"player_id"`
+}
+
+// CancelGameRequest represents the data needed to cancel a waiting game
+type CancelGameRequest struct {
+	GameID   primitive.ObjectID `json:"game_id"`
+	PlayerID primitive.ObjectID `json:"player_id"`
+}
+
+// ClaimAbandonmentRequest represents the data needed to claim a game the opponent abandoned
+type ClaimAbandonmentRequest struct {
+	GameID   primitive.ObjectID    `json:"game_id"`
+	PlayerID primitive.ObjectID    `json:"player_id"`
+	Claim    game.AbandonmentClaim `json:"claim"` // "win" or "draw"
+}
+
+// ClaimDrawRequest represents the data needed to claim a draw
+type ClaimDrawRequest struct {
 	GameID   primitive.ObjectID `json:"game_id"`
 	PlayerID primitive.ObjectID `json:"player_id"`
 }
 
 // GameResponse represents the response for game operations
 type GameResponse struct {
-	Message string     `json:"message"`
-	Game    *game.Game `json:"game,omitempty"`
-	GameID  string     `json:"game_id,omitempty"`
+	Message  string     `json:"message"`
+	Game     *game.Game `json:"game,omitempty"`
+	GameID   string     `json:"game_id,omitempty"`
+	Deadline *time.Time `json:"deadline,omitempty"` // When the side to move runs out of time
+
+	InviteCode string `json:"invite_code,omitempty"` // Only returned to the creator of a private game
 }
 
 // GameListResponse represents the response for listing games
@@ -61,7 +133,12 @@ type GameService interface {
 	// JoinGame allows a player to join an existing game
 	JoinGame(ctx context.Context, req JoinGameRequest) (*GameResponse, error)
 
-	// GetGame retrieves a game by ID
+	// StartGame creates a game with both players seated, skipping the waiting state.
+	// Unlike CreateGame followed by JoinGame, the game is never open for someone
+	// else to join in between.
+	StartGame(ctx context.Context, req StartGameRequest) (*GameResponse, error)
+
+	// GetGame retrieves a game by ID, for its players or for spectators if the game allows them
 	GetGame(ctx context.Context, gameID primitive.ObjectID, playerID primitive.ObjectID) (*game.Game, error)
 
 	// MakeMove processes a move in a game
@@ -70,10 +147,46 @@ type GameService interface {
 	// ResignGame allows a player to resign from a game
 	ResignGame(ctx context.Context, req ResignGameRequest) (*GameResponse, error)
 
+	// AbortGame calls a game off without a result before both players have moved
+	AbortGame(ctx context.Context, req AbortGameRequest) (*GameResponse, error)
+
+	// ClaimDraw ends a game as a draw by threefold repetition or the fifty-move rule
+	ClaimDraw(ctx context.Context, req ClaimDrawRequest) (*GameResponse, error)
+
+	// CancelGame removes a game its creator no longer wants to play, as long as no
+	// opponent has joined
+	CancelGame(ctx context.Context, req CancelGameRequest) (*GameResponse, error)
+
+	// ClaimAbandonment ends a game whose opponent has been gone for longer than the
+	// grace period, at most AbandonmentGracePeriod, as a win for the player or a draw
+	ClaimAbandonment(ctx context.Context, req ClaimAbandonmentRequest) (*GameResponse, error)
+
+	// RecordPresence records that a player is connected to a game.
+	// Live connections call it every PresenceHeartbeat.
+	RecordPresence(ctx context.Context, gameID primitive.ObjectID, playerID primitive.ObjectID) error
+
+	// OfferDraw offers the opponent a draw, open until they answer it or make a move
+	OfferDraw(ctx context.Context, req DrawOfferRequest) (*GameResponse, error)
+
+	// AcceptDraw accepts the opponent's draw offer, ending the game by agreement
+	AcceptDraw(ctx context.Context, req DrawOfferRequest) (*GameResponse, error)
+
+	// DeclineDraw declines the opponent's draw offer
+	DeclineDraw(ctx context.Context, req DrawOfferRequest) (*GameResponse, error)
+
+	// RequestTakeback asks the opponent to let the player take back their last move
+	RequestTakeback(ctx context.Context, req TakebackRequest) (*GameResponse, error)
+
+	// AcceptTakeback takes back the opponent's last move, and the player's reply if any
+	AcceptTakeback(ctx context.Context, req TakebackRequest) (*GameResponse, error)
+
+	// DeclineTakeback declines the opponent's takeback request
+	DeclineTakeback(ctx context.Context, req TakebackRequest) (*GameResponse, error)
+
 	// ListPlayerGames retrieves all games for a specific player
 	ListPlayerGames(ctx context.Context, playerID primitive.ObjectID, page, limit int) (*GameListResponse, error)
 
-	// ListWaitingGames retrieves all games waiting for players
+	// ListWaitingGames retrieves all public games waiting for players
 	ListWaitingGames(ctx context.Context, page, limit int) (*GameListResponse, error)
 
 	// ListActiveGames retrieves all active games
@@ -82,12 +195,46 @@ type GameService interface {
 	// GetGameHistory retrieves the move history of a game
 	GetGameHistory(ctx context.Context, gameID primitive.ObjectID, playerID primitive.ObjectID) ([]game.Move, error)
 
+	// GetLegalMoves retrieves the legal moves for the side to move, optionally only those from one square
+	GetLegalMoves(ctx context.Context, gameID primitive.ObjectID, playerID primitive.ObjectID, from string) ([]game.LegalMove, error)
+
 	// IsPlayerInGame checks if a player is part of a specific game
 	IsPlayerInGame(ctx context.Context, gameID primitive.ObjectID, playerID primitive.ObjectID) (bool, error)
 
 	// GetPlayerStats retrieves statistics for a player
 	GetPlayerStats(ctx context.Context, playerID primitive.ObjectID) (map[string]interface{}, error)
 
+	// GetRatingHistory retrieves a player's latest rating changes in a category, newest first
+	GetRatingHistory(ctx context.Context, playerID primitive.ObjectID, category rating.Category, limit int) ([]*rating.HistoryEntry, error)
+
+	// ExpireTimedOutGames finishes active games whose side to move has run out of time
+	// and returns how many games were finished
+	ExpireTimedOutGames(ctx context.Context) (int, error)
+
+	// ExpireCorrespondenceGames finishes correspondence games whose move deadline has
+	// passed and returns how many games were finished
+	ExpireCorrespondenceGames(ctx context.Context) (int, error)
+
+	// AbortUnplayedGames aborts active games whose side to move has not made their
+	// first move within the window and returns how many games were aborted
+	AbortUnplayedGames(ctx context.Context, window time.Duration) (int, error)
+
+	// ExpireWaitingGames abandons games that have been waiting for an opponent for
+	// longer than the ttl and returns how many games were abandoned
+	ExpireWaitingGames(ctx context.Context, ttl time.Duration) (int, error)
+
+	// RateGames rates the decided rated games whose rating failed when they ended and
+	// returns how many games were rated
+	RateGames(ctx context.Context) (int, error)
+
+	// WatchGame counts a connection of a user as watching a game for a while.
+	// Live connections call it every SpectatorHeartbeat.
+	WatchGame(ctx context.Context, gameID primitive.ObjectID, userID primitive.ObjectID, connectionID string) error
+
+	// UnwatchGame stops counting a connection of a user as watching a game. The user
+	// stays a spectator while another of their connections is watching.
+	UnwatchGame(ctx context.Context, gameID primitive.ObjectID, userID primitive.ObjectID, connectionID string) error
+
 	// DeleteGame removes a game (admin function)
 	DeleteGame(ctx context.Context, gameID primitive.ObjectID) error
 }
\ No newline at end of file
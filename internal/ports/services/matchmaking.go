@@ -0,0 +1,44 @@
+// Package services defines the interfaces for business logic services.
+// This is part of the Ports layer in Hexagonal Architecture.
+// These interfaces define the contracts for application services.
+package services
+
+import (
+	"context"
+
+	"chess-backend/internal/domain/game"
+	"chess-backend/internal/domain/matchmaking"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SeekRequest represents the data needed to look for an opponent
+type SeekRequest struct {
+	PlayerID    primitive.ObjectID `json:"player_id"`
+	TimeControl *game.TimeControl  `json:"time_control,omitempty"` // Omit for an untimed game
+	Rated       bool               `json:"rated"`                  // Rated seeks need a time control and are only paired with each other
+}
+
+// SeekResponse represents the response for matchmaking operations
+type SeekResponse struct {
+	Message string            `json:"message"`
+	Seek    *matchmaking.Seek `json:"seek,omitempty"`
+	GameID  string            `json:"game_id,omitempty"` // Set once the seek is matched
+}
+
+// MatchmakingService defines the interface for pairing players looking for a game
+type MatchmakingService interface {
+	// Seek puts a player in the queue for a time control, replacing any previous seek.
+	// The player is paired right away if a suitable opponent is waiting.
+	Seek(ctx context.Context, req SeekRequest) (*SeekResponse, error)
+
+	// GetSeek retrieves the seek of a player. A matched seek carries the ID of the
+	// new game. Players following their event stream are also sent a matched event.
+	GetSeek(ctx context.Context, playerID primitive.ObjectID) (*SeekResponse, error)
+
+	// CancelSeek takes a player out of the queue
+	CancelSeek(ctx context.Context, playerID primitive.ObjectID) error
+
+	// MatchSeeks pairs waiting players in every queue and returns how many games were created
+	MatchSeeks(ctx context.Context) (int, error)
+}
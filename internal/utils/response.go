@@ -71,6 +71,11 @@ func (rw *ResponseWriter) WriteNotFound(w http.ResponseWriter, message string) {
 	rw.WriteError(w, http.StatusNotFound, message)
 }
 
+// WriteUnprocessableEntity writes an unprocessable entity error response
+func (rw *ResponseWriter) WriteUnprocessableEntity(w http.ResponseWriter, message string) {
+	rw.WriteError(w, http.StatusUnprocessableEntity, message)
+}
+
 // WriteInternalServerError writes an internal server error response
 func (rw *ResponseWriter) WriteInternalServerError(w http.ResponseWriter, message string) {
 	rw.WriteError(w, http.StatusInternalServerError, message)